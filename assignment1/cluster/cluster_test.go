@@ -0,0 +1,98 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// addWorker connects a worker running Work in this process.
+func addWorker(c *Coordinator, name string, slots int) {
+	coord, worker := net.Pipe()
+	go func() {
+		defer worker.Close()
+		Work(worker, worker, slots)
+	}()
+	c.AddWorker(name, coord)
+}
+
+func TestCount(t *testing.T) {
+	for n := 1; n <= 10; n++ {
+		want := (nqueen.Bitmask{}).Count(n)
+		for _, mirror := range []bool{false, true} {
+			c := NewCoordinator(n, 2, mirror)
+			addWorker(c, "a", 1)
+			addWorker(c, "b", 3)
+			got, err := c.Count(context.Background(), nil)
+			if err != nil || got != want {
+				t.Errorf("For n=%d, mirror=%v, expected %d solutions, but got %d (%v)", n, mirror, want, got, err)
+			}
+		}
+	}
+}
+
+func TestWorkerDeath(t *testing.T) {
+
+	// A worker that takes two subtrees and dies without answering
+	c := NewCoordinator(9, 3, false)
+	coord, worker := net.Pipe()
+	c.AddWorker("dying", coord)
+	enc := json.NewEncoder(worker)
+	enc.Encode(Hello{Slots: 2})
+	in := bufio.NewScanner(worker)
+	for i := 0; i < 2; i++ {
+		if !in.Scan() {
+			t.Fatalf("expected a task, but the connection ended: %v", in.Err())
+		}
+	}
+	worker.Close()
+
+	addWorker(c, "healthy", 2)
+	var last nqueen.Progress
+	got, err := c.Count(context.Background(), func(p nqueen.Progress) { last = p })
+	if err != nil || got != 352 {
+		t.Errorf("expected 352 solutions, but got %d (%v)", got, err)
+	}
+	if last.UnitsDone != c.Subtrees() || last.BranchesDone != 9 {
+		t.Errorf("expected all %d subtrees and 9 branches done, but got %+v", c.Subtrees(), last)
+	}
+}
+
+func TestWorkerError(t *testing.T) {
+	c := NewCoordinator(8, 2, false)
+	c.subtrees[0] = []int{0, 1}
+	addWorker(c, "a", 1)
+	if _, err := c.Count(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "attacked") {
+		t.Errorf("expected the worker's error, but got %v", err)
+	}
+}
+
+func TestWork(t *testing.T) {
+
+	// The worker answers every task, then returns when its input ends
+	in := strings.NewReader(`{"id":7,"n":8,"prefix":[0]}` + "\n" + `{"id":9,"n":8,"prefix":[0,0]}` + "\n")
+	var out strings.Builder
+	if err := Work(in, &out, 1); err != nil {
+		t.Fatalf("Work: %v", err)
+	}
+	want := `{"slots":1}` + "\n" + `{"id":7,"count":4}` + "\n"
+	if !strings.HasPrefix(out.String(), want) || !strings.Contains(out.String(), `"id":9,"count":0,"error":`) {
+		t.Errorf("expected %q followed by an error for task 9, but got %q", want, out.String())
+	}
+}
+
+func TestCountCancel(t *testing.T) {
+
+	// Without workers nothing finishes, and a done context ends the count
+	c := NewCoordinator(8, 2, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := c.Count(ctx, nil); got != 0 || err != context.Canceled {
+		t.Errorf("expected 0 solutions and context.Canceled, but got %d (%v)", got, err)
+	}
+}
@@ -0,0 +1,272 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// Coordinator hands the subtrees of one count to the workers added to it.
+// Workers may join at any time, before or during Count, and a worker whose
+// connection fails has its unanswered subtrees handed to the others.
+type Coordinator struct {
+	n        int
+	mirror   bool
+	subtrees [][]int
+
+	// Logf, if not nil, is told when workers join and leave
+	Logf func(format string, args ...any)
+
+	todo     chan int // indices of subtrees waiting for a worker
+	results  chan workerResult
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// workerResult is a result and the worker it came from.
+type workerResult struct {
+	Result
+	worker string
+}
+
+// NewCoordinator returns a coordinator for a count of an n×n board, split
+// into subtrees on the first depth rows as by nqueen.Subtrees.
+func NewCoordinator(n, depth int, mirror bool) *Coordinator {
+	c := &Coordinator{
+		n:        n,
+		mirror:   mirror,
+		subtrees: nqueen.Subtrees(n, depth, mirror),
+		results:  make(chan workerResult),
+		stop:     make(chan struct{}),
+	}
+	// Room for every subtree, so handing one back never blocks
+	c.todo = make(chan int, len(c.subtrees))
+	for i := range c.subtrees {
+		c.todo <- i
+	}
+	return c
+}
+
+// Subtrees returns the number of subtrees the count is split into.
+func (c *Coordinator) Subtrees() int {
+	return len(c.subtrees)
+}
+
+func (c *Coordinator) logf(format string, args ...any) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+// AddWorker starts handing subtrees to the worker at the other end of conn,
+// named name in the log, and closes conn once the count is over.
+func (c *Coordinator) AddWorker(name string, conn io.ReadWriteCloser) {
+	go func() {
+		defer conn.Close()
+		c.serve(name, conn)
+	}()
+}
+
+// Serve adds a worker for every connection accepted on l, until l is closed.
+func (c *Coordinator) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		c.AddWorker(conn.RemoteAddr().String(), conn)
+	}
+}
+
+// pipeConn joins the pipes to a worker process.
+type pipeConn struct {
+	io.ReadCloser
+	stdin io.WriteCloser
+}
+
+func (p pipeConn) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+// Close ends the worker's input, which tells it to exit.
+func (p pipeConn) Close() error {
+	err := p.stdin.Close()
+	if rerr := p.ReadCloser.Close(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// Spawn starts cmd as a worker speaking the protocol on its stdin and stdout.
+func (c *Coordinator) Spawn(name string, cmd *exec.Cmd) error {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	c.AddWorker(name, pipeConn{stdout, stdin})
+	// Reap the process; if it dies early its stdout closes and its
+	// subtrees go back to the queue
+	go cmd.Wait()
+	return nil
+}
+
+// serve runs the conversation with one worker until the count is over or
+// the worker's connection fails, then hands its unanswered subtrees back.
+func (c *Coordinator) serve(name string, conn io.ReadWriter) {
+	// The reader hands over the hello, then the results, until the
+	// connection fails or is closed when serve returns
+	hellos := make(chan Hello, 1)
+	replies := make(chan Result)
+	go func() {
+		defer close(replies)
+		dec := json.NewDecoder(conn)
+		var hello Hello
+		if err := dec.Decode(&hello); err != nil {
+			close(hellos)
+			return
+		}
+		hellos <- hello
+		for {
+			var r Result
+			if err := dec.Decode(&r); err != nil {
+				return
+			}
+			select {
+			case replies <- r:
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+
+	var hello Hello
+	select {
+	case h, ok := <-hellos:
+		if !ok || h.Slots <= 0 {
+			c.logf("worker %s sent no valid hello; dropping it", name)
+			return
+		}
+		hello = h
+	case <-c.stop:
+		return
+	}
+	c.logf("worker %s joined (slots: %d)", name, hello.Slots)
+
+	enc := json.NewEncoder(conn)
+	pending := map[int]bool{}
+	defer func() {
+		for id := range pending {
+			c.todo <- id
+		}
+	}()
+	for {
+		var take chan int
+		if len(pending) < hello.Slots {
+			take = c.todo
+		}
+		select {
+		case id := <-take:
+			pending[id] = true
+			if err := enc.Encode(Task{ID: id, N: c.n, Prefix: c.subtrees[id]}); err != nil {
+				c.logf("worker %s lost (%v); subtrees back in the queue: %d", name, err, len(pending))
+				return
+			}
+		case r, ok := <-replies:
+			if !ok {
+				select {
+				case <-c.stop:
+					// The count is over; the connection closed with it
+				default:
+					c.logf("worker %s lost; subtrees back in the queue: %d", name, len(pending))
+				}
+				return
+			}
+			if !pending[r.ID] {
+				c.logf("worker %s answered task %d it was not given; dropping it", name, r.ID)
+				return
+			}
+			delete(pending, r.ID)
+			select {
+			case c.results <- workerResult{r, name}:
+			case <-c.stop:
+				return
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Count waits for the workers to count every subtree and returns the total.
+// It reports progress like nqueen.ContextCounter, counting one unit per
+// subtree, and once ctx is done returns the solutions in the subtrees that
+// finished with ctx.Err(). A worker that fails to count a subtree ends the
+// count with its error. Count may only be called once; when it returns every
+// worker is told to stop by closing its connection.
+func (c *Coordinator) Count(ctx context.Context, progress func(nqueen.Progress)) (int, error) {
+	defer c.stopOnce.Do(func() { close(c.stop) })
+
+	start := time.Now()
+	p := nqueen.Progress{Branches: c.n, Units: len(c.subtrees)}
+	if c.mirror {
+		p.Branches = (c.n + 1) / 2
+	}
+	remaining := make([]int, p.Branches)
+	for _, prefix := range c.subtrees {
+		remaining[prefix[0]]++
+	}
+	for _, r := range remaining {
+		if r == 0 {
+			p.BranchesDone++
+		}
+	}
+
+	done := make([]bool, len(c.subtrees))
+	for p.UnitsDone < p.Units {
+		select {
+		case r := <-c.results:
+			if r.Error != "" {
+				return p.Count, fmt.Errorf("worker %s: %s", r.worker, r.Error)
+			}
+			if done[r.ID] {
+				continue
+			}
+			done[r.ID] = true
+			first := c.subtrees[r.ID][0]
+			// Every solution with the first queen off the middle column has a mirror image
+			if c.mirror && 2*first != c.n-1 {
+				r.Count *= 2
+			}
+			p.Count += r.Count
+			p.UnitsDone++
+			if remaining[first]--; remaining[first] == 0 {
+				p.BranchesDone++
+			}
+			p.Elapsed = time.Since(start)
+			if progress != nil {
+				progress(p)
+			}
+		case <-ctx.Done():
+			return p.Count, ctx.Err()
+		}
+	}
+	return p.Count, nil
+}
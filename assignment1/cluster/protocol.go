@@ -0,0 +1,89 @@
+// Package cluster spreads a bitmask count over worker processes, on one
+// machine or many. A coordinator splits the search into subtrees named by
+// the columns of their first queens and hands them to workers over a line
+// protocol: each line is one JSON object. A worker opens with a Hello, then
+// answers every Task with a Result, and exits when its input ends. Workers
+// talk over a TCP connection or over stdin and stdout; a worker that drops
+// its connection has its unanswered subtrees handed to the others.
+package cluster
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// Hello is the first line a worker sends: how many tasks it runs at once.
+type Hello struct {
+	Slots int `json:"slots"`
+}
+
+// Task asks a worker to count the solutions of an n×n board that start with
+// the queens in Prefix, one per row from the top.
+type Task struct {
+	ID     int   `json:"id"`
+	N      int   `json:"n"`
+	Prefix []int `json:"prefix"`
+}
+
+// Result answers the task with the same ID. Error is set, and Count is
+// meaningless, if the worker could not count the subtree.
+type Result struct {
+	ID    int    `json:"id"`
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"`
+}
+
+// Work runs a worker: it writes a Hello to w, then counts each task read
+// from r, up to slots at a time, and writes its result to w. Zero or less
+// slots uses one per CPU. It returns nil once r ends and every task read
+// has been answered.
+func Work(r io.Reader, w io.Writer, slots int) error {
+	if slots <= 0 {
+		slots = runtime.NumCPU()
+	}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(Hello{Slots: slots}); err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex // guards enc and writeErr
+		writeErr error
+		wg       sync.WaitGroup
+	)
+	running := make(chan struct{}, slots)
+	dec := json.NewDecoder(r)
+	var err error
+	for {
+		var t Task
+		if err = dec.Decode(&t); err != nil {
+			break
+		}
+		running <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := Result{ID: t.ID}
+			count, err := nqueen.CountSubtree(t.N, t.Prefix)
+			if err != nil {
+				res.Error = err.Error()
+			}
+			res.Count = count
+			mu.Lock()
+			if writeErr == nil {
+				writeErr = enc.Encode(res)
+			}
+			mu.Unlock()
+			<-running
+		}()
+	}
+	wg.Wait()
+	if err != io.EOF {
+		return err
+	}
+	return writeErr
+}
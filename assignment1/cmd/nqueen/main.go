@@ -0,0 +1,1160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/cluster"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/csp"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/dlx"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/render"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/sat"
+)
+
+// heuristics maps -heuristic names to the informed search heuristics
+var heuristics = map[string]nqueen.Heuristic{
+	"rows":     nqueen.RemainingRows,
+	"attacked": nqueen.AttackedCells,
+	"lcv":      nqueen.LeastConstraining,
+}
+
+// atExit holds cleanups, such as flushing the -out file, that must also run
+// when the command stops early with an error
+var atExit []func()
+
+// exit runs the atExit cleanups and ends the command with code
+func exit(code int) {
+	for _, f := range atExit {
+		f()
+	}
+	os.Exit(code)
+}
+
+func main() {
+	algo := flag.String("algo", "bitmask", "search algorithm: bitmask, permutation, bfs, iddfs, astar, bestfirst, csp, dlx, sat, minconflicts, hillclimb, anneal or genetic")
+	workers := flag.Int("workers", 1, "number of goroutines counting bitmask subtrees in parallel; 0 uses all CPUs")
+	show := flag.Bool("show", false, "print the first solution found")
+	mirror := flag.Bool("mirror", false, "only search the left half of the first row and mirror the results")
+	symmetry := flag.Bool("symmetry", false, "also report solutions that are distinct up to rotation and reflection")
+	seed := flag.Int64("seed", 1, "random seed for local search and -sample")
+	maxSteps := flag.Int("max-steps", 0, "local search moves per restart; 0 uses 100 times the board size")
+	restarts := flag.Int("restarts", 10, "random restarts allowed for local search")
+	sideways := flag.Int("sideways", 0, "consecutive sideways moves allowed in hill climbing")
+	scheduleName := flag.String("schedule", "exp", "annealing cooling schedule: exp, linear or log")
+	temp := flag.Float64("temp", 2, "initial annealing temperature")
+	cooling := flag.Float64("cooling", 0.999, "per-step cooling factor for the exp schedule")
+	trials := flag.Int("trials", 1, "run local search this many times with consecutive seeds and report statistics")
+	population := flag.Int("population", 100, "genetic algorithm population size")
+	generations := flag.Int("generations", 1000, "genetic algorithm generations before giving up")
+	selection := flag.String("selection", "tournament", "genetic algorithm parent selection: tournament or roulette")
+	mutation := flag.Float64("mutation", 0.2, "genetic algorithm probability of mutating a child")
+	elite := flag.Int("elite", 2, "genetic algorithm chromosomes kept unchanged each generation")
+	fixedFlag := flag.String("fixed", "", "pre-placed queens as row:col pairs, e.g. \"0:3,4:1\"; counts their completions")
+	boardPath := flag.String("board", "", "read pre-placed queens from an ASCII grid file (Q for a queen, . for empty)")
+	variant := flag.String("variant", "standard", "attack model: standard, toroidal (wrap-around diagonals) or superqueen (queen plus knight moves)")
+	blockedFlag := flag.String("blocked", "", "squares that may not hold a queen, as row:col pairs")
+	outPath := flag.String("out", "", "stream every solution to this file; - writes to stdout")
+	format := flag.String("format", "", "export format: csv or jsonl; defaults to the -out file extension")
+	gaLog := flag.String("ga-log", "", "write per-generation fitness of the genetic algorithm to this CSV file")
+	heuristic := flag.String("heuristic", "rows", "astar/bestfirst heuristic: rows, attacked or lcv")
+	firstOnly := flag.Bool("first", false, "stop frontier searches at the first solution")
+	propagation := flag.String("propagation", "all", "csp propagation: none, fc (forward checking), ac3 or all to compare them")
+	ordering := flag.String("ordering", "mrv", "csp variable ordering: static, mrv or degree (MRV with a degree tie-break)")
+	cnfPath := flag.String("cnf", "", "write the instance, with any fixed queens, as DIMACS CNF to this file instead of solving; - writes to stdout")
+	statsPath := flag.String("stats", "", "profile the search tree (nodes and prunes per depth, leaves, time) as JSON Lines to this file instead of the usual report; - writes to stdout")
+	timeout := flag.Duration("timeout", 0, "stop a bitmask count after this long, e.g. 30s, and report the partial count")
+	progressEvery := flag.Duration("progress", 0, "report the progress of a bitmask count to stderr at this interval, e.g. 5s")
+	checkpointPath := flag.String("checkpoint", "", "save the finished subtrees of a bitmask count to this file and resume from it if it exists")
+	checkpointEvery := flag.Duration("checkpoint-every", time.Minute, "how often to save the -checkpoint file")
+	serveAddr := flag.String("serve", "", "coordinate a distributed bitmask count, handing subtrees to workers that connect to this TCP address, e.g. :7777")
+	spawn := flag.Int("spawn", 0, "start this many local worker processes for a distributed count")
+	split := flag.Int("split", 2, "rows placed in each subtree of a distributed count")
+	workerAddr := flag.String("worker", "", "count subtrees for the coordinator at this TCP address; - talks over stdin and stdout")
+	renderPath := flag.String("render", "", "draw the first solution to this SVG or PNG file")
+	framesPath := flag.String("frames", "", "draw each step of the permutation backtracking search to numbered SVG or PNG files named after this path, e.g. trace.png")
+	maxFrames := flag.Int("max-frames", 500, "most -frames pictures to draw; 0 draws every step")
+	eventsPath := flag.String("events", "", "log each step of the permutation backtracking search (place, prune, backtrack, solution) as JSON Lines to this file for ./replay; - writes to stdout")
+	maxEvents := flag.Int("max-events", 0, "most -events steps to log; 0 logs every step")
+	attacks := flag.Bool("attacks", false, "overlay the row, column and diagonals each queen attacks on -render and -frames pictures")
+	validate := flag.String("validate", "", "check a board instead of solving: a permutation such as \"1,3,0,2\", FEN-like ranks such as \"1Q2/3Q/Q3/2Q1\", a file holding either or an ASCII grid, or - to read from stdin")
+	sampleK := flag.Int("sample", 0, "draw this many random solutions instead of counting")
+	samplerName := flag.String("sampler", "auto", "-sample method: uniform (exact subtree counts), lasvegas (randomized restarts, any n, not exactly uniform) or auto (uniform up to n=14)")
+	dominate := flag.Bool("dominate", false, "find the fewest queens that attack or occupy every square, and count such sets")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./nqueen [flags] <board size>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// A worker learns the board size from each task
+	if *workerAddr != "" {
+		if err := runWorker(*workerAddr, *workers); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+
+	// A board to validate carries its own size, so the size argument is optional
+	if *validate != "" && flag.NArg() <= 1 {
+		runValidate(*validate, flag.Arg(0))
+		return
+	}
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return
+	}
+	if *workers < 0 {
+		fmt.Println("Please provide a non-negative number of workers.")
+		return
+	}
+
+	n, err := strconv.Atoi(flag.Arg(0))
+	if err != nil || n <= 0 {
+		fmt.Println("Please provide a positive integer for board size.")
+		return
+	}
+
+	if *dominate {
+		runDomination(n, *show)
+		return
+	}
+
+	if *framesPath != "" {
+		count, err := render.Frames(*framesPath, n, nqueen.Permutation{Mirror: *mirror}.Trace(n), *maxFrames, *attacks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Drew %d steps of the %d-Queens backtracking search, from %s to %s\n", count, n, render.FramePath(*framesPath, 1), render.FramePath(*framesPath, count))
+		return
+	}
+
+	if *eventsPath != "" {
+		if n > nqueen.MaxEventN {
+			fmt.Printf("-events logs boards up to %d.\n", nqueen.MaxEventN)
+			return
+		}
+		writeEvents(*eventsPath, n, *mirror, *maxEvents)
+		return
+	}
+
+	if *statsPath != "" && (*outPath != "" || *symmetry || *firstOnly) {
+		fmt.Println("-stats profiles a full count; drop -out, -symmetry and -first.")
+		return
+	}
+
+	var fixed map[int]int
+	if *fixedFlag != "" && *boardPath != "" {
+		fmt.Println("Use either -fixed or -board, not both.")
+		return
+	}
+	if *fixedFlag != "" {
+		if fixed, err = nqueen.ParseFixed(*fixedFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+	}
+	if *boardPath != "" {
+		if fixed, err = readBoardFile(*boardPath, n); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+	}
+	if fixed != nil {
+		if err := (nqueen.Completion{Fixed: fixed}).Validate(n); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		if *algo != "bitmask" && *algo != "sat" || *workers != 1 || *mirror {
+			fmt.Println("Fixed queens are completed by the sequential bitmask solver or -algo sat; drop -workers and -mirror.")
+			return
+		}
+	}
+
+	var model nqueen.Model
+	switch *variant {
+	case "standard":
+	case "toroidal":
+		model = nqueen.Toroidal{}
+	case "superqueen":
+		model = nqueen.SuperQueen{}
+	default:
+		fmt.Printf("Unknown variant %q: use standard, toroidal or superqueen.\n", *variant)
+		return
+	}
+	if *blockedFlag != "" {
+		squares, err := nqueen.ParseSquares(*blockedFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		if model == nil {
+			model = nqueen.Standard{}
+		}
+		blocked := nqueen.NewBlocked(model, squares)
+		if err := blocked.Validate(n); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		model = blocked
+	}
+	if model != nil && (*algo != "bitmask" || *workers != 1 || *mirror || fixed != nil) {
+		fmt.Println("Variants and blocked squares use their own sequential solver; drop -algo, -workers, -mirror and -fixed.")
+		return
+	}
+	if *renderPath != "" && (*algo != "bitmask" && *algo != "permutation" && *algo != "dlx" || model != nil && *attacks) {
+		fmt.Println("-render draws the first solution of -algo bitmask, permutation or dlx; -attacks draws standard queen moves, so drop it with -variant and -blocked.")
+		return
+	}
+
+	if *cnfPath != "" || *algo == "sat" {
+		if model != nil {
+			fmt.Println("The SAT encoding covers the standard board only; drop -variant and -blocked.")
+			return
+		}
+		if *algo == "sat" && *statsPath != "" {
+			fmt.Println("-stats profiles search trees; the SAT solver reports its own statistics.")
+			return
+		}
+		if *cnfPath != "" {
+			writeCNF(*cnfPath, n, fixed)
+		} else {
+			runSAT(n, fixed, *show)
+		}
+		return
+	}
+
+	// Reject -out where nothing would be exported before creating the file
+	if *outPath != "" {
+		switch {
+		case *algo == "minconflicts" || *algo == "hillclimb" || *algo == "anneal" || *algo == "genetic":
+			fmt.Println("Local search finds a single board and exports nothing; drop -out and use -show to print it.")
+			return
+		case *serveAddr != "" || *spawn > 0 || *timeout > 0 || *progressEvery > 0 || *checkpointPath != "":
+			fmt.Println("-out streams the solutions of a complete search; drop -serve, -spawn, -timeout, -progress and -checkpoint.")
+			return
+		}
+	}
+
+	var out *nqueen.SolutionWriter
+	if *outPath != "" {
+		var closeOut func() error
+		out, closeOut, err = openExport(*outPath, *format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		finish := sync.OnceValue(closeOut)
+		atExit = append(atExit, func() {
+			if err := finish(); err != nil {
+				fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *outPath, err)
+			}
+		})
+		defer func() {
+			if err := finish(); err != nil {
+				fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *outPath, err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if *sampleK > 0 {
+		if fixed != nil || model != nil || *symmetry || *statsPath != "" {
+			fmt.Println("-sample draws from the standard board; drop -fixed, -variant, -blocked, -symmetry and -stats.")
+			return
+		}
+		runSample(n, *sampleK, *samplerName, *seed, *show, out, *outPath)
+		return
+	}
+
+	var solve func(seed int64) nqueen.LocalResult
+	switch *algo {
+	case "minconflicts":
+		solve = func(seed int64) nqueen.LocalResult {
+			return nqueen.MinConflicts{MaxSteps: *maxSteps, Restarts: *restarts, Seed: seed}.Solve(n)
+		}
+	case "hillclimb":
+		solve = func(seed int64) nqueen.LocalResult {
+			return nqueen.HillClimbing{MaxSideways: *sideways, Restarts: *restarts, Seed: seed}.Solve(n)
+		}
+	case "anneal":
+		steps := *maxSteps
+		if steps <= 0 {
+			steps = 1000 * n
+		}
+		var schedule nqueen.Schedule
+		switch *scheduleName {
+		case "exp":
+			schedule = nqueen.ExponentialCooling(*temp, *cooling)
+		case "linear":
+			schedule = nqueen.LinearCooling(*temp, steps)
+		case "log":
+			schedule = nqueen.LogarithmicCooling(*temp)
+		default:
+			fmt.Printf("Unknown schedule %q: use exp, linear or log.\n", *scheduleName)
+			return
+		}
+		solve = func(seed int64) nqueen.LocalResult {
+			return nqueen.Annealing{Schedule: schedule, MaxSteps: steps, Restarts: *restarts, Seed: seed}.Solve(n)
+		}
+	case "genetic":
+		var sel nqueen.Selection
+		switch *selection {
+		case "tournament":
+			sel = nqueen.TournamentSelection(3)
+		case "roulette":
+			sel = nqueen.RouletteSelection
+		default:
+			fmt.Printf("Unknown selection %q: use tournament or roulette.\n", *selection)
+			return
+		}
+		ga := nqueen.Genetic{Population: *population, Generations: *generations, Selection: sel, MutationRate: *mutation, Elite: *elite}
+		if *gaLog != "" {
+			if *trials > 1 {
+				fmt.Println("The generation log records a single run; drop -trials to use -ga-log.")
+				return
+			}
+			f, err := os.Create(*gaLog)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error creating log: %v\n", err)
+				exit(1)
+			}
+			defer func() {
+				if err := f.Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *gaLog, err)
+					os.Exit(1)
+				}
+			}()
+			ga.Log = f
+		}
+		solve = func(seed int64) nqueen.LocalResult {
+			ga.Seed = seed
+			res, err := ga.SolveWithLog(n)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *gaLog, err)
+				exit(1)
+			}
+			return res
+		}
+	}
+	if solve != nil {
+		if *statsPath != "" {
+			fmt.Println("-stats profiles search trees; local search has none.")
+			return
+		}
+		if *trials > 1 {
+			printTrials(n, nqueen.RunTrials(*trials, *seed, solve))
+		} else {
+			printLocalResult(n, solve(*seed), *show)
+		}
+		return
+	}
+
+	if *algo == "astar" || *algo == "bestfirst" {
+		h, ok := heuristics[*heuristic]
+		if !ok {
+			fmt.Printf("Unknown heuristic %q: use rows, attacked or lcv.\n", *heuristic)
+			return
+		}
+		if n > nqueen.MaxBitmaskN {
+			fmt.Printf("The %s solver supports boards up to %d.\n", *algo, nqueen.MaxBitmaskN)
+			return
+		}
+		a := nqueen.AStar{Heuristic: h, Greedy: *algo == "bestfirst"}
+		if *statsPath != "" {
+			writeProfiles(*statsPath, profileWith(*algo, a.Profile(n)))
+			return
+		}
+		runFrontierSearch(n, a.Search, *firstOnly, *show, out)
+		return
+	}
+	if *algo == "bfs" || *algo == "iddfs" {
+		if n > nqueen.MaxBitmaskN {
+			fmt.Printf("The %s solver supports boards up to %d.\n", *algo, nqueen.MaxBitmaskN)
+			return
+		}
+		if *statsPath != "" {
+			var p nqueen.Profiler = nqueen.BFS{}
+			if *algo == "iddfs" {
+				p = nqueen.IDDFS{}
+			}
+			writeProfiles(*statsPath, profileWith(*algo, p.Profile(n)))
+			return
+		}
+		if *algo == "bfs" {
+			runFrontierSearch(n, nqueen.BFS{}.Search, *firstOnly, *show, out)
+		} else {
+			runFrontierSearch(n, nqueen.IDDFS{}.Search, *firstOnly, *show, out)
+		}
+		return
+	}
+
+	if *algo == "csp" {
+		order, err := csp.ParseOrdering(*ordering)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		levels := csp.Propagations
+		if *propagation != "all" {
+			prop, err := csp.ParsePropagation(*propagation)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				exit(1)
+			}
+			levels = []csp.Propagation{prop}
+		}
+		if *statsPath != "" {
+			var profiles []*profile.Profile
+			for _, prop := range levels {
+				s := csp.Search{Propagation: prop, Ordering: order}
+				profiles = append(profiles, profileWith(fmt.Sprintf("csp/%v/%v", prop, order), s.Profile(csp.NQueens(n))))
+			}
+			writeProfiles(*statsPath, profiles...)
+			return
+		}
+		runCSP(n, levels, order, *firstOnly, *show, out)
+		return
+	}
+
+	var solver nqueen.Solver
+	switch *algo {
+	case "permutation":
+		if *workers != 1 {
+			fmt.Println("The permutation solver runs single-threaded; use -algo bitmask with -workers.")
+			return
+		}
+		solver = nqueen.Permutation{Mirror: *mirror}
+	case "dlx":
+		if *workers != 1 || *mirror {
+			fmt.Println("The dlx solver runs single-threaded without the mirror symmetry break.")
+			return
+		}
+		solver = dlx.Queens{}
+	case "bitmask":
+		if n > nqueen.MaxBitmaskN {
+			fmt.Printf("The bitmask solver supports boards up to %d.\n", nqueen.MaxBitmaskN)
+			return
+		}
+		if model != nil {
+			solver = nqueen.Variant{Model: model}
+		} else if fixed != nil {
+			solver = nqueen.Completion{Fixed: fixed}
+		} else if *workers == 1 {
+			solver = nqueen.Bitmask{Mirror: *mirror}
+		} else if *mirror {
+			fmt.Println("The mirror symmetry break is not available with -workers.")
+			return
+		} else {
+			solver = nqueen.Parallel{Workers: *workers}
+		}
+	default:
+		fmt.Printf("Unknown algorithm %q: use bitmask, permutation, bfs, iddfs, astar, bestfirst, csp, dlx, sat, minconflicts, hillclimb, anneal or genetic.\n", *algo)
+		return
+	}
+
+	if *serveAddr != "" || *spawn > 0 {
+		if *algo != "bitmask" || *workers != 1 || model != nil || fixed != nil || out != nil || *symmetry || *statsPath != "" || *checkpointPath != "" {
+			fmt.Println("A distributed count is a plain -algo bitmask count; drop -workers, -fixed, -variant, -out, -symmetry, -stats and -checkpoint.")
+			return
+		}
+		if *split < 1 || *spawn < 0 {
+			fmt.Println("Please provide a positive -split and a non-negative -spawn.")
+			return
+		}
+		count, err := coordinate(n, *split, *mirror, *serveAddr, *spawn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		countWithContext(n, count, *timeout, *progressEvery)
+		return
+	}
+
+	if *statsPath != "" {
+		p, ok := solver.(nqueen.Profiler)
+		if !ok {
+			fmt.Printf("The %s solver cannot profile its search.\n", *algo)
+			return
+		}
+		writeProfiles(*statsPath, profileWith(*algo, p.Profile(n)))
+		return
+	}
+
+	if *timeout > 0 || *progressEvery > 0 || *checkpointPath != "" {
+		c, ok := solver.(nqueen.ContextCounter)
+		if !ok || out != nil || *symmetry {
+			fmt.Println("-timeout, -progress and -checkpoint apply to plain counts of -algo bitmask; drop -fixed, -variant, -out and -symmetry.")
+			return
+		}
+		count := func(ctx context.Context, progress func(nqueen.Progress)) (int, error) {
+			return c.CountContext(ctx, n, progress)
+		}
+		if *checkpointPath != "" {
+			if count, err = checkpointed(*checkpointPath, n, solver.(nqueen.Resumer), *mirror, *checkpointEvery); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				exit(1)
+			}
+		}
+		if *show {
+			if locs, ok := solver.First(n); ok {
+				nqueen.PrintLocs(os.Stdout, locs)
+			}
+		}
+		if *renderPath != "" {
+			renderFirst(*renderPath, n, solver, *attacks)
+		}
+		countWithContext(n, count, *timeout, *progressEvery)
+		return
+	}
+
+	if fixed != nil {
+		reportCompletion(n, solver, len(fixed), out)
+		return
+	}
+
+	if *show {
+		if locs, ok := solver.First(n); ok {
+			nqueen.PrintLocs(os.Stdout, locs)
+		}
+	}
+	if *renderPath != "" {
+		renderFirst(*renderPath, n, solver, *attacks)
+	}
+	if out != nil {
+		total, err := nqueen.Export(out, solver.Solutions(n))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *outPath, err)
+			exit(1)
+		}
+		if !*symmetry {
+			fmt.Printf("Total solutions for %d-Queens: %d (written to %s)\n", n, total, *outPath)
+			return
+		}
+	}
+	if !*symmetry {
+		fmt.Printf("Total solutions for %d-Queens: %d\n", n, solver.Count(n))
+		return
+	}
+
+	report := nqueen.ReduceSymmetry(solver, n)
+	fmt.Printf("Total solutions for %d-Queens: %d\n", n, report.Total)
+	fmt.Printf("Fundamental solutions (up to rotation and reflection): %d\n", report.Fundamental)
+	sizes := make([]int, 0, len(report.Orbits))
+	for size := range report.Orbits {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	for _, size := range sizes {
+		fmt.Printf("  orbit size %d: %d\n", size, report.Orbits[size])
+	}
+}
+
+// maxUniformN is the largest board -sampler auto draws from uniformly; its
+// first sample costs a full count
+const maxUniformN = 14
+
+// runSample draws k random solutions with the named sampler, printing each
+// one or drawing it when show is set, or exporting them to out if set
+func runSample(n, k int, name string, seed int64, show bool, out *nqueen.SolutionWriter, outPath string) {
+	if name == "auto" {
+		name = "lasvegas"
+		if n <= maxUniformN {
+			name = "uniform"
+		}
+	}
+	var sampler nqueen.Sampler
+	switch name {
+	case "uniform":
+		if n > nqueen.MaxBitmaskN {
+			fmt.Printf("The uniform sampler supports boards up to %d; use -sampler lasvegas.\n", nqueen.MaxBitmaskN)
+			return
+		}
+		sampler = nqueen.UniformSampler{Seed: seed}
+	case "lasvegas":
+		sampler = nqueen.LasVegasSampler{Seed: seed}
+	default:
+		fmt.Printf("Unknown sampler %q: use uniform, lasvegas or auto.\n", name)
+		return
+	}
+
+	// The Las Vegas sampler also reports how often it started over
+	samples := sampler.Samples(n, k)
+	restarts := 0
+	if lv, ok := sampler.(nqueen.LasVegasSampler); ok {
+		samples = func(yield func([]int) bool) {
+			lv.SamplesWithStats(n, k, func(locs []int, stats nqueen.LasVegasStats) bool {
+				restarts += stats.Restarts
+				return yield(locs)
+			})
+		}
+	}
+
+	start := time.Now()
+	drawn := 0
+	if out != nil {
+		var err error
+		if drawn, err = nqueen.Export(out, samples); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing %s: %v\n", outPath, err)
+			exit(1)
+		}
+	} else {
+		for locs := range samples {
+			drawn++
+			if show {
+				nqueen.PrintLocs(os.Stdout, locs)
+			} else {
+				fmt.Println(locs)
+			}
+		}
+	}
+	if drawn == 0 {
+		fmt.Printf("%d-Queens has no solution to sample.\n", n)
+		return
+	}
+	fmt.Printf("Drew %d %s samples of %d-Queens with seed %d in %v\n", drawn, name, n, seed, time.Since(start).Round(time.Millisecond))
+	if name == "lasvegas" {
+		fmt.Printf("Restarts: %d (%.2f per sample)\n", restarts, float64(restarts)/float64(drawn))
+	}
+}
+
+// renderFirst draws the first solution found by solver to path
+func renderFirst(path string, n int, solver nqueen.Solver, attacks bool) {
+	locs, ok := solver.First(n)
+	if !ok {
+		fmt.Printf("%d-Queens has no solution to draw.\n", n)
+		return
+	}
+	b := render.Board{N: n, Locs: locs, Attacks: attacks, Title: fmt.Sprintf("%d-Queens", n)}
+	if err := b.Save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		exit(1)
+	}
+	fmt.Printf("Drew the first solution to %s\n", path)
+}
+
+// runValidate reads the board given by spec and reports whether it solves
+// N-Queens, listing every pair of queens that attack each other; it exits
+// with status 1 if the board is not a solution
+func runValidate(spec, size string) {
+	text := spec
+	if spec == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		text = string(data)
+	} else if info, err := os.Stat(spec); err == nil && info.Mode().IsRegular() {
+		data, err := os.ReadFile(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		text = string(data)
+	}
+	p, err := nqueen.ParsePlacement(text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		exit(1)
+	}
+	if size != "" {
+		if n, err := strconv.Atoi(size); err != nil || n != p.N {
+			fmt.Printf("The board is %d×%d but the board size given is %s.\n", p.N, p.N, size)
+			exit(1)
+		}
+	}
+
+	fmt.Printf("Read a %d×%d board with %d queens (%s):\n", p.N, p.N, len(p.Queens), p.Format)
+	nqueen.PrintSquares(os.Stdout, p.N, p.Queens)
+	conflicts := nqueen.ConflictingPairs(p.Queens)
+	for _, c := range conflicts {
+		fmt.Printf("  %d:%d and %d:%d share a %v\n", c.A.Row, c.A.Col, c.B.Row, c.B.Col, c.Kind)
+	}
+	if p.IsSolution() {
+		fmt.Printf("Valid solution of %d-Queens.\n", p.N)
+		return
+	}
+	fmt.Printf("Not a solution of %d-Queens: %d of %d queens placed, %d conflicting pairs.\n", p.N, len(p.Queens), p.N, len(conflicts))
+	exit(1)
+}
+
+// runDomination prints the domination number of an n×n board and the number
+// of minimum dominating sets, drawing one of them when show is set
+func runDomination(n int, show bool) {
+	gamma, set := nqueen.DominationNumber(n)
+	fmt.Printf("Queens needed to dominate a %d×%d board: %d\n", n, n, gamma)
+	if show {
+		nqueen.PrintSquares(os.Stdout, n, set)
+	}
+	count := 0
+	for range nqueen.MinimumDominatingSets(n) {
+		count++
+	}
+	fmt.Printf("Minimum dominating sets: %d\n", count)
+}
+
+// printLocalResult reports a local-search run, checking the board before declaring success
+func printLocalResult(n int, res nqueen.LocalResult, show bool) {
+	if !res.Solved {
+		fmt.Printf("No solution found for %d-Queens after %d steps and %d restarts\n", n, res.Steps, res.Restarts)
+		return
+	}
+	// AttackingPairs checks columns and diagonals in linear time, so even n=10^6 is checked quickly
+	if nqueen.AttackingPairs(res.Locs) != 0 {
+		fmt.Printf("Local search returned an invalid board for %d-Queens\n", n)
+		exit(1)
+	}
+	if show {
+		nqueen.PrintLocs(os.Stdout, res.Locs)
+	}
+	fmt.Printf("Found a solution for %d-Queens after %d steps and %d restarts\n", n, res.Steps, res.Restarts)
+}
+
+// printTrials reports a batch of seeded local-search runs
+func printTrials(n int, stats nqueen.TrialStats) {
+	fmt.Printf("Local search on %d-Queens over %d trials\n", n, stats.Trials)
+	fmt.Printf("Success rate: %.2f%% (%d/%d)\n", stats.SuccessRate*100, stats.Successes, stats.Trials)
+	fmt.Printf("Average steps: %.2f (success %.2f, failure %.2f)\n", stats.AvgSteps, stats.AvgStepsSuccess, stats.AvgStepsFailure)
+	fmt.Printf("Average restarts: %.2f\n", stats.AvgRestarts)
+}
+
+// runFrontierSearch runs a search with an explicit frontier and reports its statistics
+// Every solution reached is also written to out when it is not nil
+func runFrontierSearch(n int, search func(int, func([]int) bool) nqueen.SearchStats, firstOnly, show bool, out *nqueen.SolutionWriter) {
+	var found []int
+	var writeErr error
+	stats := search(n, func(locs []int) bool {
+		if found == nil {
+			found = append([]int(nil), locs...)
+		}
+		if out != nil {
+			if writeErr = out.Write(locs); writeErr != nil {
+				return false
+			}
+		}
+		return !firstOnly
+	})
+	if writeErr != nil {
+		fmt.Fprintf(os.Stderr, "error writing solutions: %v\n", writeErr)
+		exit(1)
+	}
+
+	if show && found != nil {
+		nqueen.PrintLocs(os.Stdout, found)
+	}
+	if firstOnly {
+		if found == nil {
+			fmt.Printf("No solution exists for %d-Queens\n", n)
+		} else {
+			fmt.Printf("Found a solution for %d-Queens\n", n)
+		}
+	} else {
+		fmt.Printf("Total solutions for %d-Queens: %d\n", n, stats.Solutions)
+	}
+	fmt.Printf("Nodes expanded: %d\n", stats.Expanded)
+	fmt.Printf("Nodes generated: %d\n", stats.Generated)
+	fmt.Printf("Max frontier size: %d nodes (%d bytes)\n", stats.MaxFrontier, stats.MaxFrontierBytes)
+	fmt.Printf("Time: %v\n", stats.Elapsed)
+}
+
+// runCSP solves N-Queens as a constraint satisfaction problem at each
+// propagation level and reports the search statistics of each. Solutions are
+// shown and written to out for the first level only.
+func runCSP(n int, levels []csp.Propagation, order csp.Ordering, firstOnly, show bool, out *nqueen.SolutionWriter) {
+	p := csp.NQueens(n)
+	fmt.Printf("CSP search on %d-Queens with %v ordering\n", n, order)
+	for i, prop := range levels {
+		var found []int
+		var writeErr error
+		stats := csp.Search{Propagation: prop, Ordering: order}.Solve(p, func(locs []int) bool {
+			if found == nil {
+				found = append([]int(nil), locs...)
+			}
+			if out != nil && i == 0 {
+				if writeErr = out.Write(locs); writeErr != nil {
+					return false
+				}
+			}
+			return !firstOnly
+		})
+		if writeErr != nil {
+			fmt.Fprintf(os.Stderr, "error writing solutions: %v\n", writeErr)
+			exit(1)
+		}
+		if show && i == 0 && found != nil {
+			nqueen.PrintLocs(os.Stdout, found)
+		}
+		fmt.Printf("%-4s solutions %d, assignments %d, dead ends %d, pruned %d, checks %d, time %v\n",
+			prop, stats.Solutions, stats.Assignments, stats.DeadEnds, stats.Pruned, stats.Checks, stats.Elapsed)
+	}
+}
+
+// writeCNF writes the SAT encoding of the board to path in DIMACS format
+func writeCNF(path string, n int, fixed map[int]int) {
+	f := sat.Queens(n, fixed)
+	comments := []string{
+		fmt.Sprintf("%d-Queens: variable r*%d+c+1 is true when a queen stands on row r, column c", n, n),
+	}
+	if len(fixed) > 0 {
+		comments = append(comments, fmt.Sprintf("fixed queens as unit clauses: %d", len(fixed)))
+	}
+	var err error
+	if path == "-" {
+		err = f.WriteDIMACS(os.Stdout, comments...)
+	} else {
+		var file *os.File
+		if file, err = os.Create(path); err == nil {
+			err = f.WriteDIMACS(file, comments...)
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", path, err)
+		exit(1)
+	}
+	if path != "-" {
+		fmt.Printf("Wrote %d variables and %d clauses to %s\n", f.NumVars, len(f.Clauses), path)
+	}
+}
+
+// runSAT solves the board with the CDCL solver and reports its statistics
+func runSAT(n int, fixed map[int]int, show bool) {
+	model, ok, stats := sat.Solve(sat.Queens(n, fixed))
+	switch {
+	case ok && show:
+		nqueen.PrintLocs(os.Stdout, sat.DecodeQueens(n, model))
+		fallthrough
+	case ok:
+		fmt.Printf("Found a solution for %d-Queens\n", n)
+	case len(fixed) > 0:
+		fmt.Printf("Completable: no, the fixed queens cannot be extended to a %d-Queens solution\n", n)
+	default:
+		fmt.Printf("No solution exists for %d-Queens\n", n)
+	}
+	fmt.Printf("Decisions: %d\n", stats.Decisions)
+	fmt.Printf("Conflicts: %d\n", stats.Conflicts)
+	fmt.Printf("Propagations: %d\n", stats.Propagations)
+	fmt.Printf("Learned clauses: %d\n", stats.Learned)
+	fmt.Printf("Restarts: %d\n", stats.Restarts)
+	fmt.Printf("Time: %v\n", stats.Elapsed)
+}
+
+// countFunc runs a cancellable count, calling progress as subtrees finish
+type countFunc func(ctx context.Context, progress func(nqueen.Progress)) (int, error)
+
+// checkpointed returns a count that resumes from the checkpoint at path, if
+// there is one, and saves it every interval and once the count stops
+func checkpointed(path string, n int, r nqueen.Resumer, mirror bool, every time.Duration) (countFunc, error) {
+	cp := nqueen.NewCheckpoint(n, mirror)
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		cp, err = nqueen.ReadCheckpoint(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if cp.N != n {
+			return nil, fmt.Errorf("%s: checkpoint is for %d-Queens, not %d-Queens", path, cp.N, n)
+		}
+		fmt.Printf("Resuming from %s with %d finished subtrees\n", path, len(cp.Done))
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	return func(ctx context.Context, progress func(nqueen.Progress)) (int, error) {
+		lastSave := time.Now()
+		total, err := r.CountFrom(ctx, cp, func(p nqueen.Progress) {
+			if time.Since(lastSave) >= every {
+				lastSave = time.Now()
+				if err := saveCheckpoint(path, cp); err != nil {
+					fmt.Fprintf(os.Stderr, "error saving checkpoint: %v\n", err)
+				}
+			}
+			progress(p)
+		})
+		if err := saveCheckpoint(path, cp); err != nil {
+			fmt.Fprintf(os.Stderr, "error saving checkpoint: %v\n", err)
+		}
+		return total, err
+	}, nil
+}
+
+// saveCheckpoint replaces the checkpoint at path, writing to a temporary
+// file first so a crash mid-write leaves the previous checkpoint intact
+func saveCheckpoint(path string, cp *nqueen.Checkpoint) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = cp.Write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// coordinate returns a count that hands the subtrees of an n×n board to the
+// workers connecting to addr, if set, and to spawn local worker processes
+func coordinate(n, split int, mirror bool, addr string, spawn int) (countFunc, error) {
+	c := cluster.NewCoordinator(n, split, mirror)
+	c.Logf = func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+	var l net.Listener
+	if addr != "" {
+		var err error
+		if l, err = net.Listen("tcp", addr); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Waiting for workers on %s (%d subtrees to count)\n", l.Addr(), c.Subtrees())
+		go c.Serve(l)
+	}
+	if spawn > 0 {
+		self, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i <= spawn; i++ {
+			cmd := exec.Command(self, "-worker", "-")
+			cmd.Stderr = os.Stderr
+			if err := c.Spawn(fmt.Sprintf("local-%d", i), cmd); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return func(ctx context.Context, progress func(nqueen.Progress)) (int, error) {
+		if l != nil {
+			defer l.Close()
+		}
+		return c.Count(ctx, progress)
+	}, nil
+}
+
+// runWorker counts subtrees for a coordinator, over stdin and stdout when
+// addr is - and over a TCP connection otherwise, until the coordinator is done
+func runWorker(addr string, slots int) error {
+	if addr == "-" {
+		return cluster.Work(os.Stdin, os.Stdout, slots)
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return cluster.Work(conn, conn, slots)
+}
+
+// countWithContext counts until done, timed out or interrupted, reporting
+// progress to stderr every interval, and marks a partial count as incomplete
+func countWithContext(n int, count countFunc, timeout, every time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var last nqueen.Progress
+	lastReport := time.Now()
+	total, err := count(ctx, func(p nqueen.Progress) {
+		last = p
+		if every <= 0 || time.Since(lastReport) < every {
+			return
+		}
+		lastReport = time.Now()
+		fmt.Fprintf(os.Stderr, "progress: %d/%d first-row branches, %.1f%% of subtrees, %d solutions so far, elapsed %v, ETA %v\n",
+			p.BranchesDone, p.Branches, 100*p.Fraction(), p.Count, p.Elapsed.Round(time.Millisecond), p.ETA().Round(time.Second))
+	})
+	if err == nil {
+		fmt.Printf("Total solutions for %d-Queens: %d\n", n, total)
+		return
+	}
+	if ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		exit(1)
+	}
+
+	reason := "interrupted"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = fmt.Sprintf("timed out after %v", timeout)
+	}
+	fmt.Printf("Total solutions for %d-Queens: at least %d (incomplete: %s with %d/%d first-row branches and %.1f%% of subtrees done)\n",
+		n, total, reason, last.BranchesDone, last.Branches, 100*last.Fraction())
+}
+
+// writeEvents logs the steps of the permutation backtracking search to path
+func writeEvents(path string, n int, mirror bool, max int) {
+	w := os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		w = f
+	}
+	count, err := nqueen.WriteEvents(w, n, nqueen.Permutation{Mirror: mirror}.Trace(n), max)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", path, err)
+		exit(1)
+	}
+	if path != "-" {
+		if err := w.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing %s: %v\n", path, err)
+			exit(1)
+		}
+		fmt.Printf("Logged %d steps of the %d-Queens backtracking search to %s\n", count, n, path)
+	}
+}
+
+// profileWith names the solver of a profile
+func profileWith(solver string, p *profile.Profile) *profile.Profile {
+	p.Solver = solver
+	return p
+}
+
+// writeProfiles writes each profile as one JSON line to path and reports its count
+func writeProfiles(path string, profiles ...*profile.Profile) {
+	w := os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			exit(1)
+		}
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	for _, p := range profiles {
+		if err := enc.Encode(p); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing %s: %v\n", path, err)
+			exit(1)
+		}
+	}
+	if path == "-" {
+		return
+	}
+	if err := w.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", path, err)
+		exit(1)
+	}
+	for _, p := range profiles {
+		fmt.Printf("Total solutions for %d-Queens: %d (%s, %d nodes, %.3fs)\n", p.N, p.Solutions, p.Solver, sum(p.Nodes), p.WallSeconds)
+	}
+	fmt.Printf("Search profile written to %s\n", path)
+}
+
+// sum adds up per-depth counts
+func sum(counts []int64) int64 {
+	total := int64(0)
+	for _, c := range counts {
+		total += c
+	}
+	return total
+}
+
+// readBoardFile reads pre-placed queens from an ASCII grid drawn for an n×n board
+func readBoardFile(path string, n int) (map[int]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	size, fixed, err := nqueen.ReadBoard(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if size != n {
+		return nil, fmt.Errorf("%s: board is %d×%d but the board size is %d", path, size, size, n)
+	}
+	return fixed, nil
+}
+
+// reportCompletion prints whether the fixed queens can be completed, how many
+// completions exist and the first one, exporting every completion to out if set
+func reportCompletion(n int, solver nqueen.Solver, numFixed int, out *nqueen.SolutionWriter) {
+	fmt.Printf("Fixed queens: %d\n", numFixed)
+	example, ok := solver.First(n)
+	if !ok {
+		fmt.Printf("Completable: no, the fixed queens cannot be extended to a %d-Queens solution\n", n)
+		return
+	}
+
+	var total int
+	if out != nil {
+		var err error
+		if total, err = nqueen.Export(out, solver.Solutions(n)); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing solutions: %v\n", err)
+			exit(1)
+		}
+	} else {
+		total = solver.Count(n)
+	}
+	fmt.Println("Completable: yes")
+	fmt.Printf("Completions for %d-Queens: %d\n", n, total)
+	fmt.Println("Example completion:")
+	nqueen.PrintLocs(os.Stdout, example)
+}
+
+// openExport creates the -out destination, inferring the format from the file
+// extension (.jsonl or .json for JSON Lines, CSV otherwise) unless one is given.
+// The returned function flushes the writer and closes the file.
+func openExport(path, format string) (*nqueen.SolutionWriter, func() error, error) {
+	if format == "" {
+		switch filepath.Ext(path) {
+		case ".jsonl", ".json":
+			format = "jsonl"
+		default:
+			format = "csv"
+		}
+	}
+
+	f := os.Stdout
+	if path != "-" {
+		var err error
+		if f, err = os.Create(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	w, err := nqueen.NewSolutionWriter(f, format)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return w, func() error {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if f == os.Stdout {
+			return nil
+		}
+		return f.Close()
+	}, nil
+}
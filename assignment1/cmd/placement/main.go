@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/placement"
+)
+
+func main() {
+	pieceName := flag.String("piece", "queen", "piece type: rook, bishop, knight, king or queen")
+	rows := flag.Int("rows", 8, "number of board rows")
+	cols := flag.Int("cols", 0, "number of board columns; 0 makes the board square")
+	k := flag.Int("k", 0, "count placements of k non-attacking pieces")
+	maxFlag := flag.Bool("max", false, "find the largest number of non-attacking pieces the board holds")
+	show := flag.Bool("show", false, "print the first placement found")
+	flag.Parse()
+
+	piece, err := placement.ParsePiece(*pieceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if *cols == 0 {
+		*cols = *rows
+	}
+	if *rows <= 0 || *cols <= 0 {
+		fmt.Println("Please provide a positive board size.")
+		return
+	}
+	if *k <= 0 && !*maxFlag {
+		fmt.Println("Please provide -k N to count placements or -max to find the largest placement.")
+		return
+	}
+	b := placement.Board{Rows: *rows, Cols: *cols, Piece: piece}
+
+	if *maxFlag {
+		best, squares := b.Max()
+		fmt.Printf("Maximum non-attacking %ss on %d×%d: %d\n", piece, *rows, *cols, best)
+		if *show {
+			printBoard(b, squares)
+		}
+		return
+	}
+
+	if *show {
+		if squares, ok := b.First(*k); ok {
+			printBoard(b, squares)
+		}
+	}
+	fmt.Printf("Placements of %d non-attacking %ss on %d×%d: %d\n", *k, piece, *rows, *cols, b.Count(*k))
+}
+
+// printBoard draws the board with each piece as its letter, in the style of nqueen.PrintLocs
+func printBoard(b placement.Board, squares []nqueen.Square) {
+	letter := map[placement.Piece]string{
+		placement.Rook: "R", placement.Bishop: "B", placement.Knight: "N", placement.King: "K", placement.Queen: "Q",
+	}[b.Piece]
+	occupied := map[nqueen.Square]bool{}
+	for _, s := range squares {
+		occupied[s] = true
+	}
+	for r := 0; r < b.Rows; r++ {
+		var line strings.Builder
+		for c := 0; c < b.Cols; c++ {
+			if occupied[nqueen.Square{Row: r, Col: c}] {
+				line.WriteString(" " + letter + " ")
+			} else {
+				line.WriteString(" . ")
+			}
+		}
+		fmt.Println(line.String())
+	}
+	fmt.Println()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+func main() {
+	delay := flag.Duration("delay", 0, "pause between steps, e.g. 200ms; 0 waits for Enter (a step number jumps ahead, q quits)")
+	from := flag.Int("from", 1, "first step to show")
+	only := flag.String("only", "", "show only these events, comma separated: place, backtrack, prune and solution")
+	clearFlag := flag.Bool("clear", true, "clear the terminal before each step")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./replay [flags] <events.jsonl | ->")
+		fmt.Fprintln(flag.CommandLine.Output(), "Replays an event log written by ./nqueen -events.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return
+	}
+	var shown []nqueen.Event
+	if *only != "" {
+		for _, name := range strings.Split(*only, ",") {
+			var e nqueen.Event
+			if err := e.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				os.Exit(1)
+			}
+			shown = append(shown, e)
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if path := flag.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	} else if *delay == 0 {
+		fmt.Println("A log read from stdin leaves no way to press Enter; set -delay to replay it.")
+		return
+	}
+	keys := bufio.NewScanner(os.Stdin)
+
+	next := *from
+	last := 0
+	for s, err := range nqueen.ReadEvents(in) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		last = s.Index
+		if s.Index < next || shown != nil && !slices.Contains(shown, s.Event) {
+			continue
+		}
+		if *clearFlag {
+			fmt.Print(clearScreen)
+		}
+		printStep(os.Stdout, s)
+
+		if *delay > 0 {
+			time.Sleep(*delay)
+			continue
+		}
+		fmt.Print("[Enter] next, [step number] jump ahead, [q] quit: ")
+		if !keys.Scan() {
+			return
+		}
+		switch key := strings.TrimSpace(keys.Text()); {
+		case key == "q":
+			return
+		case key != "":
+			if step, err := strconv.Atoi(key); err == nil {
+				next = step
+			}
+		}
+	}
+	fmt.Printf("End of log after %d steps.\n", last)
+}
+
+// printStep describes one step and draws the board after it, with empty
+// rows below the queens placed so far
+func printStep(w io.Writer, s nqueen.LoggedStep) {
+	switch s.Event {
+	case nqueen.Place:
+		fmt.Fprintf(w, "step %d: place a queen on row %d, column %d\n", s.Index, s.Row, s.Col)
+	case nqueen.Backtrack:
+		fmt.Fprintf(w, "step %d: backtrack, taking the queen off row %d, column %d\n", s.Index, s.Row, s.Col)
+	case nqueen.Prune:
+		fmt.Fprintf(w, "step %d: prune the complete board, which has queens attacking on a diagonal\n", s.Index)
+	case nqueen.Found:
+		fmt.Fprintf(w, "step %d: solution %v\n", s.Index, s.Board)
+	}
+	board := make([]int, s.N)
+	for i := range board {
+		board[i] = -1
+	}
+	copy(board, s.Board)
+	nqueen.PrintLocs(w, board)
+}
@@ -0,0 +1,83 @@
+// Package csp solves small constraint satisfaction problems over integer
+// domains with binary constraints. The backtracking search can order
+// variables by minimum remaining values (MRV) with a degree tie-break, and
+// prune domains by forward checking or by maintaining arc consistency with
+// AC-3. N-Queens is provided as one instance of such a problem.
+package csp
+
+import "fmt"
+
+// Problem is a constraint satisfaction problem: variables 0..len(Domains)-1,
+// the values each may take, and binary constraints between pairs of them.
+type Problem struct {
+	Domains   [][]int
+	neighbors [][]arc
+}
+
+// arc is one direction of a binary constraint, seen from the variable that
+// owns it: ok reports whether the owner's value a is compatible with the
+// value b of variable y.
+type arc struct {
+	y  int
+	ok func(a, b int) bool
+}
+
+// NewProblem returns a problem over the given domains with no constraints.
+func NewProblem(domains [][]int) *Problem {
+	return &Problem{Domains: domains, neighbors: make([][]arc, len(domains))}
+}
+
+// Constrain adds a constraint between variables x and y that holds when
+// allowed(value of x, value of y) is true.
+func (p *Problem) Constrain(x, y int, allowed func(a, b int) bool) {
+	if x == y || x < 0 || y < 0 || x >= len(p.Domains) || y >= len(p.Domains) {
+		panic(fmt.Sprintf("csp: invalid constraint between variables %d and %d", x, y))
+	}
+	p.neighbors[x] = append(p.neighbors[x], arc{y, allowed})
+	p.neighbors[y] = append(p.neighbors[y], arc{x, func(a, b int) bool { return allowed(b, a) }})
+}
+
+// Satisfied reports whether a complete assignment meets every constraint.
+func (p *Problem) Satisfied(assign []int) bool {
+	if len(assign) != len(p.Domains) {
+		return false
+	}
+	for x, arcs := range p.neighbors {
+		for _, c := range arcs {
+			if !c.ok(assign[x], assign[c.y]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// NQueens returns N-Queens as a CSP: variable i is the column of the queen
+// in row i, and every pair of rows must differ in column and not share a
+// diagonal. Solutions are the same column lists the nqueen package uses.
+func NQueens(n int) *Problem {
+	domains := make([][]int, n)
+	for i := range domains {
+		domains[i] = make([]int, n)
+		for c := range domains[i] {
+			domains[i][c] = c
+		}
+	}
+	p := NewProblem(domains)
+	for r1 := 0; r1 < n; r1++ {
+		for r2 := r1 + 1; r2 < n; r2++ {
+			dist := r2 - r1
+			p.Constrain(r1, r2, func(a, b int) bool {
+				return a != b && abs(a-b) != dist
+			})
+		}
+	}
+	return p
+}
@@ -0,0 +1,104 @@
+package csp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+func TestNQueens(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		want := (nqueen.Bitmask{}).Count(n)
+		p := NQueens(n)
+		for _, prop := range Propagations {
+			for _, order := range []Ordering{Static, MRV, MRVDegree} {
+				s := Search{Propagation: prop, Ordering: order}
+				got := 0
+				for locs := range s.Solutions(p) {
+					if !nqueen.IsValid(locs) || !p.Satisfied(locs) {
+						t.Errorf("For n=%d, %v/%v returned invalid solution %v", n, prop, order, locs)
+					}
+					got++
+				}
+				if got != want {
+					t.Errorf("For n=%d, %v/%v expected %d solutions, but got %d", n, prop, order, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestPropagationPrunes(t *testing.T) {
+
+	// Stronger propagation never tries more values
+	p := NQueens(8)
+	var prev Stats
+	for i, prop := range Propagations {
+		stats := Search{Propagation: prop}.Solve(p, func([]int) bool { return true })
+		if i > 0 && stats.Assignments > prev.Assignments {
+			t.Errorf("expected %v to try at most %d values, but it tried %d", prop, prev.Assignments, stats.Assignments)
+		}
+		if prop == Backtracking && stats.Pruned != 0 {
+			t.Errorf("expected plain backtracking to prune nothing, but it pruned %d", stats.Pruned)
+		}
+		prev = stats
+	}
+}
+
+func TestMapColoring(t *testing.T) {
+
+	// The map of Australia from AIMA: 3 colors give 18 colorings, 2 give none
+	const (
+		wa = iota
+		nt
+		sa
+		q
+		nsw
+		v
+		tas
+	)
+	borders := [][2]int{{wa, nt}, {wa, sa}, {nt, sa}, {nt, q}, {sa, q}, {sa, nsw}, {sa, v}, {q, nsw}, {nsw, v}}
+	differ := func(a, b int) bool { return a != b }
+
+	for colors, want := range map[int]int{2: 0, 3: 18} {
+		domains := make([][]int, 7)
+		for i := range domains {
+			for c := 0; c < colors; c++ {
+				domains[i] = append(domains[i], c)
+			}
+		}
+		p := NewProblem(domains)
+		for _, b := range borders {
+			p.Constrain(b[0], b[1], differ)
+		}
+		for _, prop := range Propagations {
+			stats := Search{Propagation: prop, Ordering: MRVDegree}.Solve(p, func(assign []int) bool {
+				if !p.Satisfied(assign) {
+					t.Errorf("%v returned invalid coloring %v", prop, assign)
+				}
+				return true
+			})
+			if stats.Solutions != want {
+				t.Errorf("With %d colors, %v expected %d colorings, but got %d", colors, prop, want, stats.Solutions)
+			}
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+
+	// Plain backtracking in row order visits exactly the bitmask search tree
+	want := (nqueen.Bitmask{}).Profile(8)
+	got := Search{Propagation: Backtracking, Ordering: Static}.Profile(NQueens(8))
+	if got.Solutions != want.Solutions || !slices.Equal(got.Nodes, want.Nodes) || !slices.Equal(got.Prunes, want.Prunes) || got.Leaves != want.Leaves {
+		t.Errorf("expected the bitmask tree %v, but got %v", want.Nodes, got.Nodes)
+	}
+
+	for _, prop := range Propagations {
+		prof := Search{Propagation: prop, Ordering: MRV}.Profile(NQueens(8))
+		if prof.Solutions != 92 || prof.Nodes[8] != 92 {
+			t.Errorf("%v: expected 92 solutions, but got %d", prop, prof.Solutions)
+		}
+	}
+}
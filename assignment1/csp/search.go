@@ -0,0 +1,381 @@
+package csp
+
+import (
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Propagation is how much the search prunes domains after each assignment.
+type Propagation int
+
+const (
+	// Backtracking only checks a new value against the assigned variables.
+	Backtracking Propagation = iota
+	// ForwardChecking removes values of unassigned neighbors that conflict
+	// with the new value.
+	ForwardChecking
+	// AC3 maintains arc consistency over all unassigned variables.
+	AC3
+)
+
+// Propagations lists every propagation level, weakest first.
+var Propagations = []Propagation{Backtracking, ForwardChecking, AC3}
+
+var propagationNames = []string{"none", "fc", "ac3"}
+
+func (p Propagation) String() string {
+	if p < 0 || int(p) >= len(propagationNames) {
+		return fmt.Sprintf("Propagation(%d)", int(p))
+	}
+	return propagationNames[p]
+}
+
+// ParsePropagation returns the propagation level named none, fc or ac3.
+func ParsePropagation(name string) (Propagation, error) {
+	for i, s := range propagationNames {
+		if s == name {
+			return Propagation(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown propagation %q: use none, fc or ac3", name)
+}
+
+// Ordering is how the search picks the next variable to assign.
+type Ordering int
+
+const (
+	// Static assigns variables in index order.
+	Static Ordering = iota
+	// MRV picks the variable with the fewest values left.
+	MRV
+	// MRVDegree breaks MRV ties by the most constraints on unassigned variables.
+	MRVDegree
+)
+
+var orderingNames = []string{"static", "mrv", "degree"}
+
+func (o Ordering) String() string {
+	if o < 0 || int(o) >= len(orderingNames) {
+		return fmt.Sprintf("Ordering(%d)", int(o))
+	}
+	return orderingNames[o]
+}
+
+// ParseOrdering returns the variable ordering named static, mrv or degree.
+func ParseOrdering(name string) (Ordering, error) {
+	for i, s := range orderingNames {
+		if s == name {
+			return Ordering(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown ordering %q: use static, mrv or degree", name)
+}
+
+// Stats describes the work a search did.
+type Stats struct {
+	Solutions   int
+	Assignments int // values tried for a variable
+	DeadEnds    int // assignments rejected or wiped out a domain
+	Pruned      int // domain values removed by propagation
+	Checks      int // constraint checks
+	Elapsed     time.Duration
+}
+
+// Search is a backtracking search with a variable ordering and a
+// propagation level.
+type Search struct {
+	Propagation Propagation
+	Ordering    Ordering
+}
+
+// removal records a value taken out of a domain so it can be restored.
+type removal struct{ x, i int }
+
+// state is the search state shared down the recursion.
+type state struct {
+	p      *Problem
+	assign []int
+	// live[x][i] reports whether Domains[x][i] is still possible, and
+	// size[x] how many are
+	live  [][]bool
+	size  []int
+	trail []removal
+	stats Stats
+	prof  *profile.Profile // records every node when not nil
+}
+
+func (s *state) remove(x, i int) {
+	s.live[x][i] = false
+	s.size[x]--
+	s.trail = append(s.trail, removal{x, i})
+}
+
+// undo restores the domains to what they were when the trail had length mark.
+func (s *state) undo(mark int) {
+	for _, r := range s.trail[mark:] {
+		s.live[r.x][r.i] = true
+		s.size[r.x]++
+	}
+	s.trail = s.trail[:mark]
+}
+
+// Solve yields every solution of p, reusing the slice between calls, and
+// returns the statistics of the search. It stops early when yield returns false.
+func (c Search) Solve(p *Problem, yield func([]int) bool) Stats {
+	return c.solve(p, yield, nil)
+}
+
+// Profile counts the solutions of p and records the search tree, with depth
+// the number of variables assigned. A node considers the values left in the
+// domain of the variable it branches on; those rejected by the consistency
+// check or by propagation count as pruned.
+func (c Search) Profile(p *Problem) *profile.Profile {
+	prof := profile.New(len(p.Domains))
+	stats := c.solve(p, func([]int) bool { return true }, prof)
+	prof.Solutions = stats.Solutions
+	prof.Finish(stats.Elapsed)
+	return prof
+}
+
+// solve runs the search, recording every node in prof when it is not nil.
+func (c Search) solve(p *Problem, yield func([]int) bool, prof *profile.Profile) Stats {
+	start := time.Now()
+	s := &state{
+		prof:   prof,
+		p:      p,
+		assign: make([]int, len(p.Domains)),
+		live:   make([][]bool, len(p.Domains)),
+		size:   make([]int, len(p.Domains)),
+	}
+	for x, d := range p.Domains {
+		s.assign[x] = -1
+		s.live[x] = make([]bool, len(d))
+		for i := range d {
+			s.live[x][i] = true
+		}
+		s.size[x] = len(d)
+	}
+	consistent := true
+	if c.Propagation == AC3 {
+		consistent = s.ac3(allArcs(p))
+	}
+	if consistent {
+		c.search(s, 0, yield)
+	}
+	s.stats.Elapsed = time.Since(start)
+	return s.stats
+}
+
+// Solutions returns an iterator over every solution of p.
+func (c Search) Solutions(p *Problem) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		c.Solve(p, yield)
+	}
+}
+
+// DFS-based search over the unassigned variables
+// input: search state, number of variables assigned so far, solution callback
+// return: false if yield stopped the search
+func (c Search) search(s *state, depth int, yield func([]int) bool) bool {
+	if depth == len(s.assign) {
+		s.stats.Solutions++
+		if s.prof != nil {
+			s.prof.Record(depth, 0, 0)
+		}
+		return yield(s.assign)
+	}
+
+	x := c.selectVar(s)
+	candidates, children := s.size[x], 0
+	if s.prof != nil {
+		defer func() { s.prof.Record(depth, candidates, children) }()
+	}
+	for i, a := range s.p.Domains[x] {
+		if !s.live[x][i] {
+			continue
+		}
+		s.stats.Assignments++
+		if c.Propagation == Backtracking && !s.consistent(x, a) {
+			s.stats.DeadEnds++
+			continue
+		}
+
+		mark := len(s.trail)
+		s.assign[x] = a
+		ok := true
+		if c.Propagation != Backtracking {
+			// Reduce x to its value so propagation sees it as decided
+			for j := range s.p.Domains[x] {
+				if j != i && s.live[x][j] {
+					s.remove(x, j)
+				}
+			}
+			if c.Propagation == ForwardChecking {
+				ok = s.forwardCheck(x, a)
+			} else {
+				var queue []pair
+				for _, nb := range s.p.neighbors[x] {
+					if s.assign[nb.y] < 0 {
+						queue = append(queue, pair{nb.y, x})
+					}
+				}
+				ok = s.ac3(queue)
+			}
+		}
+		if !ok {
+			s.stats.DeadEnds++
+		} else {
+			children++
+			if !c.search(s, depth+1, yield) {
+				return false
+			}
+		}
+		s.assign[x] = -1
+		s.undo(mark)
+	}
+	return true
+}
+
+// selectVar returns the next unassigned variable under the ordering.
+func (c Search) selectVar(s *state) int {
+	best, bestDegree := -1, -1
+	for x := range s.assign {
+		if s.assign[x] >= 0 {
+			continue
+		}
+		if c.Ordering == Static {
+			return x
+		}
+		if best >= 0 && s.size[x] > s.size[best] {
+			continue
+		}
+		degree := 0
+		if c.Ordering == MRVDegree {
+			for _, nb := range s.p.neighbors[x] {
+				if s.assign[nb.y] < 0 {
+					degree++
+				}
+			}
+		}
+		if best < 0 || s.size[x] < s.size[best] || degree > bestDegree {
+			best, bestDegree = x, degree
+		}
+	}
+	return best
+}
+
+// consistent reports whether x=a agrees with every assigned neighbor.
+func (s *state) consistent(x, a int) bool {
+	for _, nb := range s.p.neighbors[x] {
+		if s.assign[nb.y] < 0 {
+			continue
+		}
+		s.stats.Checks++
+		if !nb.ok(a, s.assign[nb.y]) {
+			return false
+		}
+	}
+	return true
+}
+
+// forwardCheck removes the values of unassigned neighbors of x that conflict
+// with x=a, and reports false if a domain becomes empty.
+func (s *state) forwardCheck(x, a int) bool {
+	for _, nb := range s.p.neighbors[x] {
+		if s.assign[nb.y] >= 0 {
+			continue
+		}
+		for j, b := range s.p.Domains[nb.y] {
+			if !s.live[nb.y][j] {
+				continue
+			}
+			s.stats.Checks++
+			if !nb.ok(a, b) {
+				s.remove(nb.y, j)
+				s.stats.Pruned++
+			}
+		}
+		if s.size[nb.y] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pair is a directed arc x→y whose x domain must have support in y.
+type pair struct{ x, y int }
+
+// allArcs returns every directed arc of p.
+func allArcs(p *Problem) []pair {
+	var arcs []pair
+	for x, nbs := range p.neighbors {
+		for _, nb := range nbs {
+			arcs = append(arcs, pair{x, nb.y})
+		}
+	}
+	return arcs
+}
+
+// ac3 makes the arcs in queue, and every arc affected by the removals that
+// follow, consistent. It reports false if a domain becomes empty.
+func (s *state) ac3(queue []pair) bool {
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+		if !s.revise(a.x, a.y) {
+			continue
+		}
+		if s.size[a.x] == 0 {
+			return false
+		}
+		for _, nb := range s.p.neighbors[a.x] {
+			if nb.y != a.y {
+				queue = append(queue, pair{nb.y, a.x})
+			}
+		}
+	}
+	return true
+}
+
+// revise removes the values of x with no supporting value of y under any
+// constraint between them, and reports whether it removed anything.
+func (s *state) revise(x, y int) bool {
+	revised := false
+	for i, a := range s.p.Domains[x] {
+		if !s.live[x][i] || s.supported(x, a, y) {
+			continue
+		}
+		s.remove(x, i)
+		s.stats.Pruned++
+		revised = true
+	}
+	return revised
+}
+
+// supported reports whether some live value of y satisfies every constraint
+// between x=a and y.
+func (s *state) supported(x, a, y int) bool {
+	for j, b := range s.p.Domains[y] {
+		if !s.live[y][j] {
+			continue
+		}
+		ok := true
+		for _, nb := range s.p.neighbors[x] {
+			if nb.y != y {
+				continue
+			}
+			s.stats.Checks++
+			if !nb.ok(a, b) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+// Package dlx solves exact cover problems with Knuth's Algorithm X on
+// dancing links. Primary columns must be covered exactly once; secondary
+// columns may be covered at most once, which is what N-Queens needs for its
+// diagonals.
+package dlx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// node is a cell of the sparse matrix, or a column header. Links are indices
+// into Matrix.nodes; node 0 is the root of the header list.
+type node struct {
+	left, right, up, down int
+	col, row              int
+}
+
+// Matrix is a sparse 0/1 matrix kept as a toroidal doubly linked list.
+type Matrix struct {
+	nodes   []node
+	size    []int // ones left in each column
+	columns int
+	rows    int
+	updates int
+
+	// initial holds the column sizes before the search; prof records
+	// every node of the search when it is not nil
+	initial []int
+	prof    *profile.Profile
+}
+
+// New returns an empty matrix with the given numbers of primary and
+// secondary columns. Primary columns are 0..primary-1 and secondary columns
+// follow them.
+func New(primary, secondary int) *Matrix {
+	columns := primary + secondary
+	m := &Matrix{
+		nodes:   make([]node, columns+1),
+		size:    make([]int, columns),
+		columns: columns,
+	}
+	for i := range m.nodes {
+		m.nodes[i] = node{left: i, right: i, up: i, down: i, col: i - 1, row: -1}
+	}
+	// Only primary columns join the header list, so search never picks a
+	// secondary one but covering still removes the rows that use it
+	for c := 1; c <= primary; c++ {
+		m.nodes[c].left = c - 1
+		m.nodes[c].right = (c + 1) % (primary + 1)
+	}
+	m.nodes[0].left = primary
+	m.nodes[0].right = min(1, primary)
+	return m
+}
+
+// AddRow adds a row with ones in the given columns and returns its index.
+// Rows are numbered in the order they are added.
+func (m *Matrix) AddRow(cols ...int) int {
+	row := m.rows
+	m.rows++
+	first := -1
+	for _, c := range cols {
+		if c < 0 || c >= m.columns {
+			panic(fmt.Sprintf("dlx: column %d out of range [0, %d)", c, m.columns))
+		}
+		head := c + 1
+		i := len(m.nodes)
+		m.nodes = append(m.nodes, node{up: m.nodes[head].up, down: head, col: c, row: row})
+		m.nodes[m.nodes[head].up].down = i
+		m.nodes[head].up = i
+		m.size[c]++
+		if first < 0 {
+			first = i
+			m.nodes[i].left, m.nodes[i].right = i, i
+		} else {
+			last := m.nodes[first].left
+			m.nodes[i].left, m.nodes[i].right = last, first
+			m.nodes[last].right = i
+			m.nodes[first].left = i
+		}
+	}
+	return row
+}
+
+// cover removes column c from the header list and every row using it from
+// the other columns.
+func (m *Matrix) cover(c int) {
+	head := c + 1
+	m.nodes[m.nodes[head].right].left = m.nodes[head].left
+	m.nodes[m.nodes[head].left].right = m.nodes[head].right
+	for i := m.nodes[head].down; i != head; i = m.nodes[i].down {
+		for j := m.nodes[i].right; j != i; j = m.nodes[j].right {
+			m.nodes[m.nodes[j].down].up = m.nodes[j].up
+			m.nodes[m.nodes[j].up].down = m.nodes[j].down
+			m.size[m.nodes[j].col]--
+			m.updates++
+		}
+	}
+}
+
+// uncover undoes cover(c), relinking in the reverse order.
+func (m *Matrix) uncover(c int) {
+	head := c + 1
+	for i := m.nodes[head].up; i != head; i = m.nodes[i].up {
+		for j := m.nodes[i].left; j != i; j = m.nodes[j].left {
+			m.size[m.nodes[j].col]++
+			m.nodes[m.nodes[j].down].up = j
+			m.nodes[m.nodes[j].up].down = j
+		}
+	}
+	m.nodes[m.nodes[head].right].left = head
+	m.nodes[m.nodes[head].left].right = head
+}
+
+// Solve yields the rows of every exact cover, reusing the slice between
+// calls, and stops early when yield returns false. It returns the number of
+// covers found and the number of link updates, Knuth's measure of the work done.
+func (m *Matrix) Solve(yield func(rows []int) bool) (count, updates int) {
+	m.updates = 0
+	m.search(make([]int, 0, m.columns), &count, yield)
+	return count, m.updates
+}
+
+// Count returns the number of exact covers.
+func (m *Matrix) Count() int {
+	count, _ := m.Solve(func([]int) bool { return true })
+	return count
+}
+
+// DFS-based Algorithm X
+// input: rows chosen so far, running count of covers, solution callback
+// return: false if yield stopped the search
+func (m *Matrix) search(chosen []int, count *int, yield func([]int) bool) bool {
+	if m.nodes[0].right == 0 {
+		*count++
+		if m.prof != nil {
+			m.prof.Record(len(chosen), 0, 0)
+		}
+		return yield(chosen)
+	}
+
+	// Branch on the primary column with the fewest rows left
+	head := m.nodes[0].right
+	for c := m.nodes[head].right; c != 0; c = m.nodes[c].right {
+		if m.size[c-1] < m.size[head-1] {
+			head = c
+		}
+	}
+	if m.prof != nil {
+		m.prof.Record(len(chosen), m.initial[head-1], m.size[head-1])
+	}
+	if m.size[head-1] == 0 {
+		return true
+	}
+
+	m.cover(head - 1)
+	defer m.uncover(head - 1)
+	for i := m.nodes[head].down; i != head; i = m.nodes[i].down {
+		for j := m.nodes[i].right; j != i; j = m.nodes[j].right {
+			m.cover(m.nodes[j].col)
+		}
+		ok := m.search(append(chosen, m.nodes[i].row), count, yield)
+		for j := m.nodes[i].left; j != i; j = m.nodes[j].left {
+			m.uncover(m.nodes[j].col)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Profile counts the exact covers and records the search tree in prof, with
+// depth the number of rows chosen. A node considers every row of the column
+// it branches on; rows already removed by covering count as pruned.
+func (m *Matrix) Profile(prof *profile.Profile) {
+	start := time.Now()
+	m.initial = append([]int(nil), m.size...)
+	m.prof = prof
+	defer func() { m.prof = nil }()
+	prof.Solutions = m.Count()
+	prof.Finish(time.Since(start))
+}
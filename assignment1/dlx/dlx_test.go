@@ -0,0 +1,85 @@
+package dlx
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+func TestExactCover(t *testing.T) {
+
+	// The example from Knuth's "Dancing Links" paper, columns A-G
+	m := New(7, 0)
+	m.AddRow(2, 4, 5)
+	m.AddRow(0, 3, 6)
+	m.AddRow(1, 2, 5)
+	m.AddRow(0, 3)
+	m.AddRow(1, 6)
+	m.AddRow(3, 4, 6)
+
+	var covers [][]int
+	count, updates := m.Solve(func(rows []int) bool {
+		covers = append(covers, slices.Sorted(slices.Values(rows)))
+		return true
+	})
+	if count != 1 || len(covers) != 1 || !slices.Equal(covers[0], []int{0, 3, 4}) {
+		t.Errorf("expected the single cover [0 3 4], but got %v", covers)
+	}
+	if updates == 0 {
+		t.Errorf("expected some link updates to be counted")
+	}
+
+	// Solving again finds the same cover, so the links were restored
+	if got := m.Count(); got != 1 {
+		t.Errorf("expected 1 cover on the second run, but got %d", got)
+	}
+}
+
+func TestSecondaryColumns(t *testing.T) {
+
+	// Rows 0 and 1 share secondary column 2, so only rows 0 and 2 form a cover
+	m := New(2, 1)
+	m.AddRow(0, 2)
+	m.AddRow(1, 2)
+	m.AddRow(1)
+	if got := m.Count(); got != 1 {
+		t.Errorf("expected 1 cover, but got %d", got)
+	}
+}
+
+func TestQueens(t *testing.T) {
+	for n := 1; n <= 10; n++ {
+		want := (nqueen.Bitmask{}).Count(n)
+		if got := (Queens{}).Count(n); got != want {
+			t.Errorf("For n=%d, expected %d solutions, but got %d", n, want, got)
+		}
+	}
+
+	seen := map[[8]int]bool{}
+	for locs := range (Queens{}).Solutions(8) {
+		if !nqueen.IsValid(locs) {
+			t.Errorf("invalid solution %v", locs)
+		}
+		seen[[8]int(locs)] = true
+	}
+	if len(seen) != 92 {
+		t.Errorf("expected 92 distinct solutions, but got %d", len(seen))
+	}
+	if _, ok := (Queens{}).First(3); ok {
+		t.Errorf("expected no solution for n=3")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		want := (nqueen.Bitmask{}).Count(n)
+		prof := (Queens{}).Profile(n)
+		if prof.Solutions != want || prof.Nodes[n] != int64(want) {
+			t.Errorf("For n=%d, expected %d solutions at depth %d, but got %d (%v)", n, want, n, prof.Solutions, prof.Nodes)
+		}
+		if prof.Nodes[0] != 1 {
+			t.Errorf("For n=%d, expected one root node, but got %d", n, prof.Nodes[0])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package dlx
+
+import (
+	"iter"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// QueensMatrix encodes N-Queens as a generalized exact cover problem. Matrix
+// row r*n+c places a queen on row r, column c. Its primary columns are the n
+// rows and the n columns of the board, which every solution covers exactly
+// once; its secondary columns are the 2n-1 diagonals and 2n-1 anti-diagonals,
+// which a solution may cover at most once.
+func QueensMatrix(n int) *Matrix {
+	m := New(2*n, 2*(2*n-1))
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			m.AddRow(r, n+c, 2*n+r+c, 4*n-1+(r-c+n-1))
+		}
+	}
+	return m
+}
+
+// Queens solves N-Queens by dancing links.
+type Queens struct{}
+
+// Count returns the number of solutions on an n×n board.
+func (Queens) Count(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return QueensMatrix(n).Count()
+}
+
+// Solutions returns an iterator over every solution on an n×n board, as the
+// column of the queen in each row. The slice is reused between iterations.
+func (Queens) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		if n <= 0 {
+			return
+		}
+		locs := make([]int, n)
+		QueensMatrix(n).Solve(func(rows []int) bool {
+			for _, i := range rows {
+				locs[i/n] = i % n
+			}
+			return yield(locs)
+		})
+	}
+}
+
+// Profile records the dancing links search tree. Every chosen row places a
+// queen, so depth is the number of queens placed.
+func (Queens) Profile(n int) *profile.Profile {
+	prof := profile.New(n)
+	if n > 0 {
+		QueensMatrix(n).Profile(prof)
+	}
+	return prof
+}
+
+// First returns the first solution found, if any.
+func (q Queens) First(n int) ([]int, bool) {
+	for locs := range q.Solutions(n) {
+		return append([]int(nil), locs...), true
+	}
+	return nil, false
+}
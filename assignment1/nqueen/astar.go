@@ -0,0 +1,178 @@
+package nqueen
+
+import (
+	"container/heap"
+	"iter"
+	"math/bits"
+	"time"
+	"unsafe"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Heuristic estimates the remaining cost of a partial placement; the frontier
+// expands the node with the lowest estimate first.
+type Heuristic func(p Partial) int
+
+// RemainingRows counts the rows still to be filled. It is exact for every
+// board that can be completed, so A* with it is admissible.
+func RemainingRows(p Partial) int {
+	return p.N - p.Row()
+}
+
+// AttackedCells counts the squares in the remaining rows that are already
+// attacked, preferring boards that leave the most room for later queens.
+func AttackedCells(p Partial) int {
+	attacked := 0
+	for r := p.Row(); r < p.N; r++ {
+		attacked += p.N - p.Free(r)
+	}
+	return attacked
+}
+
+// LeastConstraining counts the safe squares in the remaining rows that the
+// last queen placed took away, so the least constraining column is tried first.
+func LeastConstraining(p Partial) int {
+	if p.Row() == 0 {
+		return 0
+	}
+	removed := 0
+	for r := p.Row(); r < p.N; r++ {
+		// The parent board saw row r one row further away
+		dist := r - p.Row()
+		before := free(p.N, dist+1, p.pcols, p.pdiag1, p.pdiag2)
+		after := free(p.N, dist, p.cols, p.diag1, p.diag2)
+		removed += bits.OnesCount64(before &^ after)
+	}
+	return removed
+}
+
+// AStar is a best-first search over partial placements. Each node places the
+// next row's queen on a safe column, and the frontier is ordered by
+// f = g + h, where g is the number of queens placed and h the heuristic.
+// With Greedy set, g is ignored and nodes are ordered by h alone.
+// Ties go to the deeper node, then to the node generated first.
+// It panics for boards larger than MaxBitmaskN.
+type AStar struct {
+	Heuristic Heuristic // nil uses RemainingRows
+	Greedy    bool
+}
+
+// searchNode is a frontier entry.
+type searchNode struct {
+	p   Partial
+	f   int
+	seq int
+}
+
+// nodeOverhead is the memory a search node adds around its board: priority, sequence and heap pointer.
+const nodeOverhead = int(unsafe.Sizeof(searchNode{})-unsafe.Sizeof(Partial{})) + int(unsafe.Sizeof(&searchNode{}))
+
+// frontier is a min-heap of search nodes.
+type frontier []*searchNode
+
+func (q frontier) Len() int { return len(q) }
+func (q frontier) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	if q[i].p.Row() != q[j].p.Row() {
+		return q[i].p.Row() > q[j].p.Row()
+	}
+	return q[i].seq < q[j].seq
+}
+func (q frontier) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *frontier) Push(x any)   { *q = append(*q, x.(*searchNode)) }
+func (q *frontier) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return x
+}
+
+// Search runs the best-first search on an n×n board, calling yield with each
+// solution in the order found; returning false from yield stops the search.
+// A nil yield counts every solution.
+func (a AStar) Search(n int, yield func([]int) bool) SearchStats {
+	return a.search(n, yield, nil)
+}
+
+// search runs the best-first search, recording every expanded node in prof when it is not nil.
+func (a AStar) search(n int, yield func([]int) bool, prof *profile.Profile) SearchStats {
+	start := time.Now()
+	h := a.Heuristic
+	if h == nil {
+		h = RemainingRows
+	}
+	// Check the board size up front; children builds its masks from it
+	fullMask(n)
+
+	var stats SearchStats
+	q := &frontier{}
+	seq, bytes := 0, 0
+	push := func(p Partial) {
+		f := h(p)
+		if !a.Greedy {
+			f += p.Row()
+		}
+		heap.Push(q, &searchNode{p: p, f: f, seq: seq})
+		seq++
+		bytes += sizeOf(p) + nodeOverhead
+		stats.Generated++
+		stats.track(q.Len(), bytes)
+	}
+
+	push(Partial{N: n})
+	for q.Len() > 0 {
+		node := heap.Pop(q).(*searchNode)
+		bytes -= sizeOf(node.p) + nodeOverhead
+		stats.Expanded++
+		if node.p.Row() == n {
+			stats.Solutions++
+			if prof != nil {
+				prof.Record(n, 0, 0)
+			}
+			if yield != nil && !yield(node.p.Locs) {
+				break
+			}
+			continue
+		}
+		kids := children(node.p)
+		if prof != nil {
+			prof.Record(node.p.Row(), n, len(kids))
+		}
+		for _, child := range kids {
+			push(child)
+		}
+	}
+	stats.Elapsed = time.Since(start)
+	return stats
+}
+
+// Count returns the number of solutions on an n×n board.
+func (a AStar) Count(n int) int {
+	return a.Search(n, nil).Solutions
+}
+
+// Solutions yields every solution in the order the frontier reaches them.
+func (a AStar) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		a.Search(n, yield)
+	}
+}
+
+// First returns the first solution the frontier reaches.
+func (a AStar) First(n int) ([]int, bool) {
+	return first(a.Solutions(n))
+}
+
+// Profile records the search tree. Every node is expanded once, so the
+// counts match the bitmask tree; only the order differs.
+func (a AStar) Profile(n int) *profile.Profile {
+	prof := profile.New(n)
+	stats := a.search(n, nil, prof)
+	prof.Solutions = stats.Solutions
+	prof.Finish(stats.Elapsed)
+	return prof
+}
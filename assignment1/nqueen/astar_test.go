@@ -0,0 +1,59 @@
+package nqueen
+
+import "testing"
+
+func TestAStar(t *testing.T) {
+	heuristics := map[string]Heuristic{
+		"rows":     RemainingRows,
+		"attacked": AttackedCells,
+		"lcv":      LeastConstraining,
+	}
+
+	// Every heuristic, with and without g, must find exactly the bitmask solutions
+	for name, h := range heuristics {
+		for _, greedy := range []bool{false, true} {
+			a := AStar{Heuristic: h, Greedy: greedy}
+			for n := 1; n <= 8; n++ {
+				want := (Bitmask{}).Count(n)
+				stats := a.Search(n, nil)
+				if stats.Solutions != want {
+					t.Errorf("%s (greedy=%v): for n=%d, expected %d solutions, but got %d", name, greedy, n, want, stats.Solutions)
+				}
+				if stats.Expanded > stats.Generated || stats.MaxFrontier > stats.Generated || stats.MaxFrontier == 0 {
+					t.Errorf("%s (greedy=%v): for n=%d, inconsistent stats %+v", name, greedy, n, stats)
+				}
+				locs, ok := a.First(n)
+				if ok != (want > 0) || (ok && !IsValid(locs)) {
+					t.Errorf("%s (greedy=%v): for n=%d, First returned %v, %v", name, greedy, n, locs, ok)
+				}
+			}
+		}
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+
+	// Queen at (0, 0) on a 4×4 board attacks column 0 and the main diagonal
+	p := children(Partial{N: 4})[0]
+	if got := RemainingRows(p); got != 3 {
+		t.Errorf("expected 3 remaining rows, but got %d", got)
+	}
+	if got := AttackedCells(p); got != 6 {
+		t.Errorf("expected 6 attacked cells, but got %d", got)
+	}
+	if got := LeastConstraining(p); got != 6 {
+		t.Errorf("expected the first queen to remove 6 squares, but got %d", got)
+	}
+
+	// A second queen at (1, 2) removes (2, 1), (2, 3) and (3, 2); (2, 2) is already attacked
+	q := children(p)[0]
+	if q.Locs[1] != 2 {
+		t.Fatalf("expected the second queen in column 2, but got %d", q.Locs[1])
+	}
+	if got := LeastConstraining(q); got != 3 {
+		t.Errorf("expected the second queen to remove 3 squares, but got %d", got)
+	}
+	if got := q.Free(2); got != 0 {
+		t.Errorf("expected no safe squares left in row 2, but got %d", got)
+	}
+}
@@ -0,0 +1,137 @@
+package nqueen
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// MaxBitmaskN is the largest board the bitmask solvers support (one bit per column).
+const MaxBitmaskN = 64
+
+// Bitmask is a backtracking search that tracks occupied columns and both
+// diagonals as bitmasks, so attacked squares are pruned as each row is placed.
+// It panics for boards larger than MaxBitmaskN.
+type Bitmask struct {
+	// Mirror restricts the first queen to the left half of the board and
+	// recovers the other half by reflection, roughly halving the search.
+	Mirror bool
+}
+
+// fullMask returns a mask with the lowest n bits set.
+func fullMask(n int) uint64 {
+	if n > MaxBitmaskN {
+		panic(fmt.Sprintf("nqueen: bitmask solver supports boards up to %d, got %d", MaxBitmaskN, n))
+	}
+	return uint64(1)<<n - 1
+}
+
+// DFS-based backtracking that prunes during placement
+// input: full - mask with the lowest n bits set, cols - occupied columns,
+// diag1/diag2 - columns attacked in the current row along each diagonal direction,
+// prof - records every node when not nil
+// return: number of valid solutions from this state
+func backtrackBitmask(full, cols, diag1, diag2 uint64, prof *profile.Profile) int {
+	// Base case: every column holds a queen
+	if cols == full {
+		if prof != nil {
+			prof.Record(bits.OnesCount64(cols), 0, 0)
+		}
+		return 1
+	}
+
+	total := 0
+	// Columns in the current row that are not attacked by any queen above
+	avail := full &^ (cols | diag1 | diag2)
+	if prof != nil {
+		prof.Record(bits.OnesCount64(cols), bits.OnesCount64(full), bits.OnesCount64(avail))
+	}
+	for avail != 0 {
+		// Take the lowest free column and remove it from the candidates
+		bit := avail & -avail
+		avail ^= bit
+		// Diagonals shift by one column per row as they move down the board
+		total += backtrackBitmask(full, cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1, prof)
+	}
+	return total
+}
+
+// Same search as backtrackBitmask, recording the column chosen in each row
+// input: locs - board being filled, row - current row to place a queen
+// yield - called with each complete board; returning false stops the search
+// return: false if the search was stopped
+func walkBitmask(full, cols, diag1, diag2 uint64, locs []int, row int, yield func([]int) bool) bool {
+	if cols == full {
+		return yield(locs)
+	}
+
+	avail := full &^ (cols | diag1 | diag2)
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		locs[row] = bits.TrailingZeros64(bit)
+		if !walkBitmask(full, cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1, locs, row+1, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of solutions on an n×n board.
+func (b Bitmask) Count(n int) int {
+	return b.count(n, nil)
+}
+
+// count runs the search, recording every node in prof when it is not nil.
+// With Mirror set, the right half of the first row counts as pruned.
+func (b Bitmask) count(n int, prof *profile.Profile) int {
+	full := fullMask(n)
+	if !b.Mirror {
+		return backtrackBitmask(full, 0, 0, 0, prof)
+	}
+
+	// Every solution with the first queen in the left half has a mirror image in the right half
+	if prof != nil {
+		prof.Record(0, n, (n+1)/2)
+	}
+	total := 0
+	for col := 0; col < (n+1)/2; col++ {
+		bit := uint64(1) << col
+		sub := backtrackBitmask(full, bit, bit<<1&full, bit>>1, prof)
+		if 2*col == n-1 {
+			total += sub
+		} else {
+			total += 2 * sub
+		}
+	}
+	return total
+}
+
+// Solutions yields every solution, ordered by the column of each row from left to right.
+// With Mirror set, each solution is immediately followed by its mirror image instead.
+func (b Bitmask) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		full := fullMask(n)
+		locs := make([]int, n)
+		if !b.Mirror {
+			walkBitmask(full, 0, 0, 0, locs, 0, yield)
+			return
+		}
+
+		emit := mirrorYield(n, yield)
+		for col := 0; col < (n+1)/2; col++ {
+			bit := uint64(1) << col
+			locs[0] = col
+			if !walkBitmask(full, bit, bit<<1&full, bit>>1, locs, 1, emit) {
+				return
+			}
+		}
+	}
+}
+
+// First returns the lexicographically smallest solution.
+func (b Bitmask) First(n int) ([]int, bool) {
+	return first(b.Solutions(n))
+}
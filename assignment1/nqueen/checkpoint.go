@@ -0,0 +1,101 @@
+package nqueen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Checkpoint records the subtrees of a bitmask count that have finished, so
+// that an interrupted count can resume without searching them again. A
+// subtree is named by the columns of the queens in the first two rows.
+type Checkpoint struct {
+	N      int            `json:"n"`
+	Mirror bool           `json:"mirror"`
+	Done   []SubtreeCount `json:"done"`
+}
+
+// SubtreeCount is the number of solutions below a two-row prefix, before
+// any mirror images are added.
+type SubtreeCount struct {
+	Prefix []int `json:"prefix"`
+	Count  int   `json:"count"`
+}
+
+// NewCheckpoint returns an empty checkpoint for a count of an n×n board.
+func NewCheckpoint(n int, mirror bool) *Checkpoint {
+	return &Checkpoint{N: n, Mirror: mirror, Done: []SubtreeCount{}}
+}
+
+// ReadCheckpoint reads a checkpoint written by Write.
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	var cp Checkpoint
+	if err := json.NewDecoder(r).Decode(&cp); err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %v", err)
+	}
+	if cp.N <= 0 || cp.N > MaxBitmaskN {
+		return nil, fmt.Errorf("checkpoint board size %d is outside 1..%d", cp.N, MaxBitmaskN)
+	}
+	return &cp, nil
+}
+
+// Write writes the checkpoint as JSON.
+func (cp *Checkpoint) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(cp)
+}
+
+// add records a finished subtree.
+func (cp *Checkpoint) add(prefix [2]int, count int) {
+	p := []int{prefix[0]}
+	if prefix[1] >= 0 {
+		p = append(p, prefix[1])
+	}
+	cp.Done = append(cp.Done, SubtreeCount{p, count})
+}
+
+// done returns the recorded counts by prefix, checking that every prefix
+// names one of the units of the count.
+func (cp *Checkpoint) done(units []branchUnit) (map[[2]int]int, error) {
+	known := map[[2]int]bool{}
+	for _, u := range units {
+		known[u.prefix] = true
+	}
+	done := map[[2]int]int{}
+	for _, s := range cp.Done {
+		key := [2]int{-1, -1}
+		copy(key[:], s.Prefix)
+		if len(s.Prefix) == 0 || len(s.Prefix) > 2 || !known[key] {
+			return nil, fmt.Errorf("checkpoint: %v is not a subtree of the %d-Queens count", s.Prefix, cp.N)
+		}
+		if _, dup := done[key]; dup {
+			return nil, fmt.Errorf("checkpoint: subtree %v is recorded twice", s.Prefix)
+		}
+		done[key] = s.Count
+	}
+	return done, nil
+}
+
+// Resumer is implemented by solvers whose counts can resume from a
+// checkpoint. CountFrom behaves like CountContext for a count of cp.N,
+// skipping the subtrees already in cp and adding each subtree that finishes
+// to cp before progress is called, so progress may save it.
+type Resumer interface {
+	CountFrom(ctx context.Context, cp *Checkpoint, progress func(Progress)) (int, error)
+}
+
+// CountFrom resumes a count from cp, which must have been made with the same Mirror.
+func (b Bitmask) CountFrom(ctx context.Context, cp *Checkpoint, progress func(Progress)) (int, error) {
+	if cp.Mirror != b.Mirror {
+		return 0, fmt.Errorf("checkpoint was made with mirror=%v", cp.Mirror)
+	}
+	return countContext(ctx, cp, 1, progress)
+}
+
+// CountFrom resumes a count from cp, which must have been made without Mirror.
+func (p Parallel) CountFrom(ctx context.Context, cp *Checkpoint, progress func(Progress)) (int, error) {
+	if cp.Mirror {
+		return 0, fmt.Errorf("checkpoint was made with mirror=true; resume it without -workers")
+	}
+	return countContext(ctx, cp, p.Workers, progress)
+}
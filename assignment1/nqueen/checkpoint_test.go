@@ -0,0 +1,83 @@
+package nqueen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	for _, mirror := range []bool{false, true} {
+		want := (Bitmask{}).Count(10)
+
+		// Interrupt a count after a few subtrees and save what finished
+		ctx, cancel := context.WithCancel(context.Background())
+		cp := NewCheckpoint(10, mirror)
+		partial, err := (Bitmask{Mirror: mirror}).CountFrom(ctx, cp, func(p Progress) {
+			if p.UnitsDone == 7 {
+				cancel()
+			}
+		})
+		if err != context.Canceled || partial >= want {
+			t.Fatalf("mirror=%v: expected an interrupted count, but got %d, %v", mirror, partial, err)
+		}
+
+		var buf bytes.Buffer
+		if err := cp.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		saved, err := ReadCheckpoint(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Resuming searches only the rest and reaches the full count
+		var first Progress
+		var resumer Resumer = Bitmask{Mirror: mirror}
+		if !mirror {
+			resumer = Parallel{Workers: 2}
+		}
+		got, err := resumer.CountFrom(context.Background(), saved, func(p Progress) {
+			if first.Units == 0 {
+				first = p
+			}
+		})
+		if err != nil || got != want {
+			t.Errorf("mirror=%v: expected the resumed count to be %d, but got %d (%v)", mirror, want, got, err)
+		}
+		if first.Resumed != len(cp.Done) || first.UnitsDone != len(cp.Done)+1 {
+			t.Errorf("mirror=%v: expected to resume %d subtrees, but got %+v", mirror, len(cp.Done), first)
+		}
+		if len(saved.Done) != first.Units {
+			t.Errorf("mirror=%v: expected the checkpoint to record all %d subtrees, but it has %d", mirror, first.Units, len(saved.Done))
+		}
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	bad := []struct {
+		json    string
+		message string
+	}{
+		{`{"n":6,"done":[{"prefix":[0,1],"count":1}]}`, "not a subtree"},
+		{`{"n":6,"done":[{"prefix":[0,2],"count":1},{"prefix":[0,2],"count":1}]}`, "recorded twice"},
+		{`{"n":6,"done":[{"prefix":[],"count":1}]}`, "not a subtree"},
+	}
+	for _, tt := range bad {
+		cp, err := ReadCheckpoint(strings.NewReader(tt.json))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := (Bitmask{}).CountFrom(context.Background(), cp, nil); err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("For %s, expected an error containing %q, but got %v", tt.json, tt.message, err)
+		}
+	}
+
+	if _, err := ReadCheckpoint(strings.NewReader(`{"n":0}`)); err == nil {
+		t.Errorf("expected an error for board size 0")
+	}
+	if _, err := (Parallel{}).CountFrom(context.Background(), NewCheckpoint(6, true), nil); err == nil {
+		t.Errorf("expected an error resuming a mirrored checkpoint in parallel")
+	}
+}
@@ -0,0 +1,150 @@
+package nqueen
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// squareSet is a set of squares of an n×n board indexed row by row.
+type squareSet []uint64
+
+func (s squareSet) set(i int) { s[i/64] |= 1 << (i % 64) }
+
+func (s squareSet) count() int {
+	c := 0
+	for _, w := range s {
+		c += bits.OnesCount64(w)
+	}
+	return c
+}
+
+// first returns the lowest member of the set, or -1 if it is empty.
+func (s squareSet) first() int {
+	for i, w := range s {
+		if w != 0 {
+			return i*64 + bits.TrailingZeros64(w)
+		}
+	}
+	return -1
+}
+
+// coverTable returns, for every square, the squares a queen on it attacks or occupies.
+func coverTable(n int) []squareSet {
+	size := n * n
+	cover := make([]squareSet, size)
+	for i := range cover {
+		cover[i] = make(squareSet, (size+63)/64)
+		r1, c1 := i/n, i%n
+		for j := 0; j < size; j++ {
+			r2, c2 := j/n, j%n
+			if r1 == r2 || c1 == c2 || onDiagonal(r1, c1, r2, c2) {
+				cover[i].set(j)
+			}
+		}
+	}
+	return cover
+}
+
+// dominate extends a partial set of queens until every square is covered.
+// It branches on the first square nobody covers yet, since some queen must
+// cover it; trying each candidate queen in turn and forbidding the earlier
+// candidates in later branches yields every set exactly once.
+// uncovered[depth] holds the squares left to cover, forbidden the queens
+// already tried by an ancestor.
+// return: false if yield stopped the search
+func dominate(cover []squareSet, uncovered []squareSet, forbidden []bool, queens []int, k, maxCover int, yield func([]int) bool) bool {
+	depth := len(queens)
+	cur := uncovered[depth]
+	u := cur.first()
+	if u < 0 {
+		return yield(queens)
+	}
+	// Even the best placed remaining queens could not cover what is left
+	if depth == k || cur.count() > (k-depth)*maxCover {
+		return true
+	}
+
+	var tried []int
+	defer func() {
+		for _, q := range tried {
+			forbidden[q] = false
+		}
+	}()
+	// Queens covering u are exactly the squares u covers
+	for q := range len(cover) {
+		if forbidden[q] || cover[u][q/64]&(1<<(q%64)) == 0 {
+			continue
+		}
+		next := uncovered[depth+1]
+		for w := range next {
+			next[w] = cur[w] &^ cover[q][w]
+		}
+		if !dominate(cover, uncovered, forbidden, append(queens, q), k, maxCover, yield) {
+			return false
+		}
+		forbidden[q] = true
+		tried = append(tried, q)
+	}
+	return true
+}
+
+// walkDominating runs dominate for sets of at most k queens on an n×n board.
+// Every set it yields dominates the board; when k is the domination number
+// these are all the minimum dominating sets.
+func walkDominating(n, k int, yield func([]int) bool) {
+	if n <= 0 || k < 0 {
+		return
+	}
+	cover := coverTable(n)
+	size := n * n
+	uncovered := make([]squareSet, k+1)
+	for i := range uncovered {
+		uncovered[i] = make(squareSet, (size+63)/64)
+	}
+	for i := 0; i < size; i++ {
+		uncovered[0].set(i)
+	}
+	// A queen covers its row, its column and at most 2(n-1) diagonal squares
+	maxCover := 4*n - 3
+	dominate(cover, uncovered, make([]bool, size), make([]int, 0, k), k, maxCover, yield)
+}
+
+// DominationNumber returns the smallest number of queens that attack or
+// occupy every square of an n×n board, and one such set of queens. It tries
+// k = 1, 2, ... and stops at the first k with a dominating set. A board of
+// size zero or less has no squares to cover and needs no queens.
+func DominationNumber(n int) (int, []Square) {
+	if n <= 0 {
+		return 0, nil
+	}
+	for k := 1; ; k++ {
+		var found []int
+		walkDominating(n, k, func(queens []int) bool {
+			found = append(found, queens...)
+			return false
+		})
+		if found != nil {
+			return k, toSquares(n, found)
+		}
+	}
+}
+
+// MinimumDominatingSets yields every minimum dominating set of queens on an
+// n×n board exactly once (OEIS A075458: 4860 sets of 5 queens for n=8).
+func MinimumDominatingSets(n int) iter.Seq[[]Square] {
+	return func(yield func([]Square) bool) {
+		k, _ := DominationNumber(n)
+		walkDominating(n, k, func(queens []int) bool {
+			return yield(toSquares(n, queens))
+		})
+	}
+}
+
+// toSquares converts square indices of an n×n board to squares.
+func toSquares(n int, idx []int) []Square {
+	set := make([]Square, len(idx))
+	for i, q := range idx {
+		set[i] = Square{q / n, q % n}
+	}
+	return set
+}
@@ -0,0 +1,85 @@
+package nqueen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDomination(t *testing.T) {
+
+	// Domination numbers and counts of minimum dominating sets (OEIS A075458)
+	tests := []struct {
+		n     int
+		gamma int
+		sets  int
+	}{
+		{1, 1, 1},
+		{2, 1, 4},
+		{3, 1, 1},
+		{4, 2, 12},
+		{5, 3, 186},
+		{6, 3, 4},
+		{7, 4, 86},
+		{8, 5, 4860},
+	}
+
+	for _, tt := range tests {
+		gamma, set := DominationNumber(tt.n)
+		if gamma != tt.gamma || !dominates(tt.n, set) {
+			t.Errorf("For n=%d, expected a dominating set of %d queens, but got %d: %v", tt.n, tt.gamma, gamma, set)
+		}
+
+		seen := map[[8]Square]bool{}
+		for set := range MinimumDominatingSets(tt.n) {
+			if len(set) != tt.gamma || !dominates(tt.n, set) {
+				t.Errorf("For n=%d, %v is not a minimum dominating set", tt.n, set)
+			}
+			var key [8]Square
+			copy(key[:], set)
+			if seen[key] {
+				t.Errorf("For n=%d, set %v was yielded twice", tt.n, set)
+			}
+			seen[key] = true
+		}
+		if len(seen) != tt.sets {
+			t.Errorf("For n=%d, expected %d minimum dominating sets, but got %d", tt.n, tt.sets, len(seen))
+		}
+	}
+
+	// Empty boards need no queens and have no sets to yield
+	for _, n := range []int{0, -1} {
+		if gamma, set := DominationNumber(n); gamma != 0 || set != nil {
+			t.Errorf("For n=%d, expected no queens, but got %d: %v", n, gamma, set)
+		}
+		for set := range MinimumDominatingSets(n) {
+			t.Errorf("For n=%d, expected no sets, but got %v", n, set)
+		}
+	}
+}
+
+// dominates checks directly that every square is attacked or occupied.
+func dominates(n int, queens []Square) bool {
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			covered := false
+			for _, q := range queens {
+				if q.Row == r || q.Col == c || onDiagonal(q.Row, q.Col, r, c) {
+					covered = true
+				}
+			}
+			if !covered {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestPrintSquares(t *testing.T) {
+	var a, b bytes.Buffer
+	PrintLocs(&a, []int{1, 3, 0, 2})
+	PrintSquares(&b, 4, []Square{{0, 1}, {1, 3}, {2, 0}, {3, 2}})
+	if a.String() != b.String() {
+		t.Errorf("PrintSquares drew\n%s\nexpected\n%s", b.String(), a.String())
+	}
+}
@@ -0,0 +1,99 @@
+package nqueen
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// LoggedStep is one line of an event log: a step of a traced search on an
+// n×n board, numbered from 1, as in
+//
+//	{"step":1,"n":4,"event":"place","row":0,"col":0,"board":[0]}
+type LoggedStep struct {
+	Index int `json:"step"`
+	N     int `json:"n"`
+	Step
+}
+
+// MaxEventN is the largest board an event log may describe. Logs are read
+// back one board at a time, so the cap keeps a corrupt size from allocating
+// an enormous board.
+const MaxEventN = 256
+
+// WriteEvents writes the steps of a traced search on an n×n board to w as
+// JSON Lines, one LoggedStep per line, until steps ends or max steps are
+// written; zero or less means no limit. It returns the number of steps
+// written, and fails for boards larger than MaxEventN.
+func WriteEvents(w io.Writer, n int, steps iter.Seq[Step], max int) (int, error) {
+	if n > MaxEventN {
+		return 0, fmt.Errorf("event logs cover boards up to %d, not %d", MaxEventN, n)
+	}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	count := 0
+	for s := range steps {
+		if max > 0 && count == max {
+			break
+		}
+		count++
+		if err := enc.Encode(LoggedStep{Index: count, N: n, Step: s}); err != nil {
+			return count - 1, err
+		}
+	}
+	return count, bw.Flush()
+}
+
+// check reports a step that does not fit its board.
+func (s LoggedStep) check() error {
+	if s.N <= 0 || s.N > MaxEventN {
+		return fmt.Errorf("board size %d is outside 1 to %d", s.N, MaxEventN)
+	}
+	if len(s.Board) > s.N {
+		return fmt.Errorf("%d queens do not fit a board of size %d", len(s.Board), s.N)
+	}
+	if s.Row < 0 || s.Row >= s.N || s.Col < 0 || s.Col >= s.N {
+		return fmt.Errorf("square %d:%d is outside the %d×%d board", s.Row, s.Col, s.N, s.N)
+	}
+	for row, col := range s.Board {
+		if col < 0 || col >= s.N {
+			return fmt.Errorf("row %d: column %d is outside the %d×%d board", row, col, s.N, s.N)
+		}
+	}
+	return nil
+}
+
+// ReadEvents yields the steps of an event log written by WriteEvents one
+// line at a time, so logs of any length can be replayed. Blank lines are
+// skipped; a line that cannot be read, or whose board is larger than
+// MaxEventN or does not hold its queens, is yielded as an error, which ends the log.
+func ReadEvents(r io.Reader) iter.Seq2[LoggedStep, error] {
+	return func(yield func(LoggedStep, error) bool) {
+		in := bufio.NewScanner(r)
+		in.Buffer(nil, 1<<20)
+		line := 0
+		for in.Scan() {
+			line++
+			if len(in.Bytes()) == 0 {
+				continue
+			}
+			var s LoggedStep
+			if err := json.Unmarshal(in.Bytes(), &s); err != nil {
+				yield(LoggedStep{}, fmt.Errorf("line %d: %v", line, err))
+				return
+			}
+			if err := s.check(); err != nil {
+				yield(LoggedStep{}, fmt.Errorf("line %d: %v", line, err))
+				return
+			}
+			if !yield(s, nil) {
+				return
+			}
+		}
+		if err := in.Err(); err != nil {
+			yield(LoggedStep{}, err)
+		}
+	}
+}
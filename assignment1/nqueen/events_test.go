@@ -0,0 +1,82 @@
+package nqueen
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	for n := 1; n <= 6; n++ {
+		trace := Permutation{Mirror: true}.Trace(n)
+		var log strings.Builder
+		count, err := WriteEvents(&log, n, trace, 0)
+		if err != nil {
+			t.Fatalf("For n=%d, WriteEvents: %v", n, err)
+		}
+
+		// The log reads back as the same steps, numbered from 1
+		var want []Step
+		for s := range trace {
+			want = append(want, Step{s.Event, s.Row, s.Col, slices.Clone(s.Board)})
+		}
+		var got []Step
+		for s, err := range ReadEvents(strings.NewReader(log.String())) {
+			if err != nil {
+				t.Fatalf("For n=%d, ReadEvents: %v", n, err)
+			}
+			if s.N != n || s.Index != len(got)+1 {
+				t.Errorf("For n=%d, expected step %d of size %d, but got %+v", n, len(got)+1, n, s)
+			}
+			got = append(got, s.Step)
+		}
+		if count != len(want) || !slices.EqualFunc(got, want, func(a, b Step) bool {
+			return a.Event == b.Event && a.Row == b.Row && a.Col == b.Col && slices.Equal(a.Board, b.Board)
+		}) {
+			t.Errorf("For n=%d, expected %d steps %v, but read %d steps %v", n, len(want), want, count, got)
+		}
+	}
+
+	// max caps the log, and events are written by name
+	var log strings.Builder
+	if count, _ := WriteEvents(&log, 4, (Permutation{}).Trace(4), 2); count != 2 {
+		t.Errorf("expected 2 steps, but got %d", count)
+	}
+	want := `{"step":1,"n":4,"event":"place","row":0,"col":0,"board":[0]}` + "\n"
+	if !strings.HasPrefix(log.String(), want) {
+		t.Errorf("expected the log to start with %q, but got %q", want, log.String())
+	}
+}
+
+func TestReadEventsErrors(t *testing.T) {
+	if _, err := WriteEvents(io.Discard, MaxEventN+1, (Permutation{}).Trace(MaxEventN+1), 1); err == nil {
+		t.Errorf("expected an error logging a board larger than %d, but got none", MaxEventN)
+	}
+
+	for _, log := range []string{
+		`{"step":1,"n":4,"event":"jump","row":0,"col":0,"board":[0]}`,
+		`{"step":1,"n":2,"event":"place","row":0,"col":0,"board":[0,1,2]}`,
+		`not json`,
+		`{"step":1,"n":1e9,"event":"place","row":0,"col":0,"board":[0]}`,
+		`{"step":1,"n":1000000000,"event":"place","row":0,"col":0,"board":[0]}`,
+		`{"step":1,"n":4,"event":"place","row":1,"col":7,"board":[0,7]}`,
+		`{"step":1,"n":4,"event":"backtrack","row":0,"col":0,"board":[-2]}`,
+	} {
+		var err error
+		for _, err = range ReadEvents(strings.NewReader(log)) {
+		}
+		if err == nil {
+			t.Errorf("For log %q, expected an error, but got none", log)
+		}
+	}
+}
+
+func TestPrintPartialLocs(t *testing.T) {
+	var out strings.Builder
+	PrintLocs(&out, []int{1, -1, -1})
+	want := " .  Q  . \n .  .  . \n .  .  . \n\n"
+	if out.String() != want {
+		t.Errorf("expected %q, but got %q", want, out.String())
+	}
+}
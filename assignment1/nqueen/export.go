@@ -0,0 +1,63 @@
+package nqueen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// SolutionWriter streams boards to w one line at a time, either as CSV (the
+// column of each row, comma separated, no header) or as JSON Lines (one JSON
+// array per line). Nothing but the current line is kept in memory.
+type SolutionWriter struct {
+	w      *bufio.Writer
+	format string
+	line   []byte
+}
+
+// NewSolutionWriter returns a writer for the "csv" or "jsonl" format.
+func NewSolutionWriter(w io.Writer, format string) (*SolutionWriter, error) {
+	if format != "csv" && format != "jsonl" {
+		return nil, fmt.Errorf("unknown export format %q: use csv or jsonl", format)
+	}
+	return &SolutionWriter{w: bufio.NewWriter(w), format: format}, nil
+}
+
+// Write appends one board.
+func (s *SolutionWriter) Write(locs []int) error {
+	s.line = s.line[:0]
+	if s.format == "jsonl" {
+		s.line = append(s.line, '[')
+	}
+	for i, c := range locs {
+		if i > 0 {
+			s.line = append(s.line, ',')
+		}
+		s.line = strconv.AppendInt(s.line, int64(c), 10)
+	}
+	if s.format == "jsonl" {
+		s.line = append(s.line, ']')
+	}
+	s.line = append(s.line, '\n')
+	_, err := s.w.Write(s.line)
+	return err
+}
+
+// Flush writes any buffered lines to the underlying writer.
+func (s *SolutionWriter) Flush() error {
+	return s.w.Flush()
+}
+
+// Export writes every board yielded by seq and returns how many were written.
+func Export(s *SolutionWriter, seq iter.Seq[[]int]) (int, error) {
+	count := 0
+	for locs := range seq {
+		if err := s.Write(locs); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, s.Flush()
+}
@@ -0,0 +1,65 @@
+package nqueen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewSolutionWriter(&buf, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := Export(w, Bitmask{}.Solutions(6))
+	if err != nil || count != 4 {
+		t.Fatalf("expected 4 solutions written, but got %d (%v)", count, err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("exported CSV does not parse: %v", err)
+	}
+	var got [][]int
+	for _, row := range rows {
+		locs := make([]int, len(row))
+		for i, v := range row {
+			locs[i], _ = strconv.Atoi(v)
+		}
+		got = append(got, locs)
+	}
+	var want [][]int
+	for locs := range (Bitmask{}).Solutions(6) {
+		want = append(want, slices.Clone(locs))
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("expected rows %v, but got %v", want, got)
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewSolutionWriter(&buf, "jsonl")
+	if _, err := Export(w, Permutation{}.Solutions(4)); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, but got %q", buf.String())
+	}
+	for _, line := range lines {
+		var locs []int
+		if err := json.Unmarshal([]byte(line), &locs); err != nil || !IsValid(locs) {
+			t.Errorf("line %q is not a valid board (%v)", line, err)
+		}
+	}
+
+	if _, err := NewSolutionWriter(&buf, "xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
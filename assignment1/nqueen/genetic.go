@@ -0,0 +1,187 @@
+package nqueen
+
+import (
+	"encoding/csv"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// Fitness returns the number of non-attacking pairs of queens on a permutation
+// board, from 0 up to n(n-1)/2 for a solution. Only the diagonals are
+// checked, as in IsValid, since a permutation never repeats a column.
+func Fitness(locs []int) int {
+	n := len(locs)
+	return n*(n-1)/2 - Conflicts(locs)
+}
+
+// Selection picks the index of a parent from a population given its fitness values.
+type Selection func(fitness []int, rng *rand.Rand) int
+
+// TournamentSelection draws k chromosomes at random and returns the fittest.
+func TournamentSelection(k int) Selection {
+	return func(fitness []int, rng *rand.Rand) int {
+		best := rng.Intn(len(fitness))
+		for i := 1; i < k; i++ {
+			if c := rng.Intn(len(fitness)); fitness[c] > fitness[best] {
+				best = c
+			}
+		}
+		return best
+	}
+}
+
+// RouletteSelection picks a chromosome with probability proportional to its fitness.
+func RouletteSelection(fitness []int, rng *rand.Rand) int {
+	total := 0
+	for _, f := range fitness {
+		total += f
+	}
+	if total == 0 {
+		return rng.Intn(len(fitness))
+	}
+	spin := rng.Intn(total)
+	for i, f := range fitness {
+		if spin < f {
+			return i
+		}
+		spin -= f
+	}
+	return len(fitness) - 1
+}
+
+// OrderCrossover is the order-preserving crossover (OX1): the child copies a
+// random slice of parent a in place and fills the remaining positions with the
+// missing columns in the order they appear in b, so it is again a permutation.
+func OrderCrossover(a, b []int, rng *rand.Rand) []int {
+	n := len(a)
+	child := make([]int, n)
+	if n == 0 {
+		return child
+	}
+	lo, hi := rng.Intn(n), rng.Intn(n)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	used := make([]bool, n)
+	for i := lo; i <= hi; i++ {
+		child[i] = a[i]
+		used[a[i]] = true
+	}
+	// Fill from just after the copied slice, wrapping around, in b's order
+	pos := (hi + 1) % n
+	for k := 0; k < n; k++ {
+		c := b[(hi+1+k)%n]
+		if used[c] {
+			continue
+		}
+		child[pos] = c
+		used[c] = true
+		pos = (pos + 1) % n
+	}
+	return child
+}
+
+// Genetic is a genetic algorithm over permutation chromosomes, the same
+// representation as the boards of the other solvers. Each generation keeps
+// the Elite fittest chromosomes unchanged and breeds the rest with Selection,
+// OrderCrossover and a swap of two genes with probability MutationRate.
+// The result reports generations as steps.
+type Genetic struct {
+	Population   int       // chromosomes per generation; zero means 100
+	Generations  int       // generations before giving up; zero means 1000
+	Selection    Selection // nil uses TournamentSelection(3)
+	MutationRate float64   // probability that a child has two genes swapped
+	Elite        int       // fittest chromosomes copied to the next generation
+	Seed         int64     // seed for the random number generator
+
+	// Log receives one CSV row per generation (generation,best,mean,worst) when set
+	Log io.Writer
+}
+
+// Solve evolves boards on an n×n board until one is a solution or the
+// generations run out. Errors writing Log are dropped; SolveWithLog reports them.
+func (g Genetic) Solve(n int) LocalResult {
+	res, _ := g.SolveWithLog(n)
+	return res
+}
+
+// SolveWithLog runs Solve and returns the first error writing Log, if any.
+func (g Genetic) SolveWithLog(n int) (LocalResult, error) {
+	if g.Log == nil {
+		return g.solve(n, nil), nil
+	}
+	log := csv.NewWriter(g.Log)
+	log.Write([]string{"generation", "best", "mean", "worst"})
+	res := g.solve(n, log)
+	log.Flush()
+	return res, log.Error()
+}
+
+// solve runs the algorithm, writing a row per generation to log when it is not nil.
+func (g Genetic) solve(n int, log *csv.Writer) LocalResult {
+	rng := rand.New(rand.NewSource(g.Seed))
+	size := g.Population
+	if size <= 0 {
+		size = 100
+	}
+	generations := g.Generations
+	if generations <= 0 {
+		generations = 1000
+	}
+	selection := g.Selection
+	if selection == nil {
+		selection = TournamentSelection(3)
+	}
+	elite := min(max(g.Elite, 0), size)
+
+	pop := make([][]int, size)
+	for i := range pop {
+		pop[i] = rng.Perm(n)
+	}
+	fitness := make([]int, size)
+	order := make([]int, size)
+	target := n * (n - 1) / 2
+
+	var res LocalResult
+	for gen := 0; ; gen++ {
+		total := 0
+		for i, locs := range pop {
+			fitness[i] = Fitness(locs)
+			total += fitness[i]
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return fitness[order[i]] > fitness[order[j]] })
+		best := order[0]
+		if log != nil {
+			mean := float64(total) / float64(size)
+			log.Write([]string{strconv.Itoa(gen), strconv.Itoa(fitness[best]),
+				strconv.FormatFloat(mean, 'f', 4, 64), strconv.Itoa(fitness[order[size-1]])})
+		}
+
+		res.Locs = append(res.Locs[:0], pop[best]...)
+		res.Steps = gen
+		if fitness[best] == target {
+			res.Solved = true
+			return res
+		}
+		if gen == generations {
+			return res
+		}
+
+		next := make([][]int, 0, size)
+		for _, i := range order[:elite] {
+			next = append(next, append([]int(nil), pop[i]...))
+		}
+		for len(next) < size {
+			child := OrderCrossover(pop[selection(fitness, rng)], pop[selection(fitness, rng)], rng)
+			if n > 1 && rng.Float64() < g.MutationRate {
+				i, j := rng.Intn(n), rng.Intn(n)
+				child[i], child[j] = child[j], child[i]
+			}
+			next = append(next, child)
+		}
+		pop = next
+	}
+}
@@ -0,0 +1,72 @@
+package nqueen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGenetic(t *testing.T) {
+	for _, sel := range []Selection{TournamentSelection(3), RouletteSelection} {
+		stats := RunTrials(10, 1, func(seed int64) LocalResult {
+			res := Genetic{Selection: sel, MutationRate: 0.3, Elite: 2, Seed: seed}.Solve(8)
+			if res.Solved && !IsValid(res.Locs) {
+				t.Errorf("seed %d reported an invalid solution %v", seed, res.Locs)
+			}
+			return res
+		})
+		if stats.Successes == 0 {
+			t.Errorf("expected the GA to solve 8-Queens at least once, but got %+v", stats)
+		}
+	}
+
+	// The log has a header and one row per generation evaluated
+	var buf bytes.Buffer
+	res := Genetic{Generations: 5, Population: 10, Seed: 1, Log: &buf}.Solve(3)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read the generation log: %v", err)
+	}
+	if res.Solved || res.Steps != 5 || len(rows) != 7 {
+		t.Errorf("For n=3, expected 5 generations and 7 log rows, but got %+v and %d rows", res, len(rows))
+	}
+	if !slices.Equal(rows[0], []string{"generation", "best", "mean", "worst"}) {
+		t.Errorf("unexpected log header %v", rows[0])
+	}
+
+	// A log that cannot be written is reported
+	if _, err := (Genetic{Generations: 5, Population: 10, Seed: 1, Log: failingWriter{}}).SolveWithLog(3); err == nil {
+		t.Errorf("expected an error writing the log, but got none")
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestOrderCrossover(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		a, b := rng.Perm(10), rng.Perm(10)
+		child := OrderCrossover(a, b, rng)
+		sorted := slices.Sorted(slices.Values(child))
+		for c := range sorted {
+			if sorted[c] != c {
+				t.Fatalf("crossover of %v and %v gave a non-permutation %v", a, b, child)
+			}
+		}
+	}
+}
+
+func TestFitness(t *testing.T) {
+	if got := Fitness([]int{1, 3, 0, 2}); got != 6 {
+		t.Errorf("expected a solution to have all 6 pairs non-attacking, but got %d", got)
+	}
+	if got := Fitness([]int{0, 1, 2, 3}); got != 0 {
+		t.Errorf("expected the main diagonal to have no non-attacking pairs, but got %d", got)
+	}
+}
@@ -0,0 +1,273 @@
+package nqueen
+
+import (
+	"math"
+	"math/rand"
+)
+
+// lineCounts counts the queens on every column and diagonal of a board that
+// holds one queen per row but, unlike a permutation, may repeat columns.
+// This is the complete-state formulation used by the textbook local searches.
+type lineCounts struct {
+	*diagonals
+	col []int
+}
+
+// newLineCounts counts the queens of locs and returns the number of attacking pairs.
+func newLineCounts(locs []int) (*lineCounts, int) {
+	l := &lineCounts{diagonals: newDiagonals(len(locs)), col: make([]int, len(locs))}
+	pairs := 0
+	for r, c := range locs {
+		pairs += l.add(r, c)
+	}
+	return l, pairs
+}
+
+// add places a queen at (r, c) and returns the number of queens it now attacks.
+func (l *lineCounts) add(r, c int) int {
+	attacked := l.col[c] + l.diagonals.add(r, c)
+	l.col[c]++
+	return attacked
+}
+
+// remove lifts the queen at (r, c) and returns the number of queens it was attacking.
+func (l *lineCounts) remove(r, c int) int {
+	l.col[c]--
+	return l.col[c] + l.diagonals.remove(r, c)
+}
+
+// move shifts the queen of row r to column c and returns the change in attacking pairs.
+func (l *lineCounts) move(locs []int, r, c int) int {
+	delta := -l.remove(r, locs[r])
+	locs[r] = c
+	return delta + l.add(r, c)
+}
+
+// AttackingPairs counts the pairs of queens that share a column or a diagonal.
+// Unlike Conflicts it accepts boards that repeat columns, so a board with one
+// queen per row is a solution exactly when AttackingPairs returns zero.
+func AttackingPairs(locs []int) int {
+	_, pairs := newLineCounts(locs)
+	return pairs
+}
+
+// randomBoard places one queen in each row on a uniformly random column.
+func randomBoard(n int, rng *rand.Rand) []int {
+	locs := make([]int, n)
+	for r := range locs {
+		locs[r] = rng.Intn(n)
+	}
+	return locs
+}
+
+// HillClimbing is steepest-ascent hill climbing over complete boards: every
+// step moves the one queen, within its row, that removes the most attacking
+// pairs. With MaxSideways zero it stops at the first plateau or local minimum;
+// otherwise it may take up to MaxSideways consecutive moves that leave the
+// number of pairs unchanged. A stuck search starts over from a new random
+// board up to Restarts times.
+type HillClimbing struct {
+	MaxSideways int   // consecutive sideways moves allowed
+	Restarts    int   // random restarts allowed after getting stuck
+	Seed        int64 // seed for the random number generator
+}
+
+// Solve searches for a single solution on an n×n board.
+func (h HillClimbing) Solve(n int) LocalResult {
+	rng := rand.New(rand.NewSource(h.Seed))
+	var res LocalResult
+	for attempt := 0; attempt <= h.Restarts; attempt++ {
+		if attempt > 0 {
+			res.Restarts++
+		}
+		locs := randomBoard(n, rng)
+		steps, solved := h.climb(locs, rng)
+		res.Steps += steps
+		res.Locs = locs
+		if solved {
+			res.Solved = true
+			return res
+		}
+	}
+	return res
+}
+
+// climb moves queens until the board is solved or no allowed move is left.
+func (h HillClimbing) climb(locs []int, rng *rand.Rand) (int, bool) {
+	n := len(locs)
+	l, pairs := newLineCounts(locs)
+	steps, sideways := 0, 0
+	type move struct{ r, c int }
+	var best []move
+	for pairs > 0 {
+		// Score every move of every queen, breaking ties at random
+		bestDelta := 0
+		best = best[:0]
+		for r := 0; r < n; r++ {
+			orig := locs[r]
+			for c := 0; c < n; c++ {
+				if c == orig {
+					continue
+				}
+				delta := l.move(locs, r, c)
+				l.move(locs, r, orig)
+				switch {
+				case len(best) == 0 || delta < bestDelta:
+					bestDelta = delta
+					best = append(best[:0], move{r, c})
+				case delta == bestDelta:
+					best = append(best, move{r, c})
+				}
+			}
+		}
+
+		if len(best) == 0 || bestDelta > 0 {
+			return steps, false
+		}
+		if bestDelta == 0 {
+			if sideways >= h.MaxSideways {
+				return steps, false
+			}
+			sideways++
+		} else {
+			sideways = 0
+		}
+		m := best[rng.Intn(len(best))]
+		pairs += l.move(locs, m.r, m.c)
+		steps++
+	}
+	return steps, true
+}
+
+// Schedule returns the annealing temperature at a given step.
+type Schedule func(step int) float64
+
+// ExponentialCooling multiplies the temperature by alpha (0 < alpha < 1) every step.
+func ExponentialCooling(t0, alpha float64) Schedule {
+	return func(step int) float64 {
+		return t0 * math.Pow(alpha, float64(step))
+	}
+}
+
+// LinearCooling lowers the temperature from t0 to zero over the given number
+// of steps; with zero steps or fewer it is frozen from the start.
+func LinearCooling(t0 float64, steps int) Schedule {
+	return func(step int) float64 {
+		if steps <= 0 {
+			return 0
+		}
+		return t0 * (1 - float64(step)/float64(steps))
+	}
+}
+
+// LogarithmicCooling is the slow schedule t0 / ln(step + 2) under which
+// annealing provably converges; it never reaches zero, so runs end at MaxSteps.
+func LogarithmicCooling(t0 float64) Schedule {
+	return func(step int) float64 {
+		return t0 / math.Log(float64(step)+2)
+	}
+}
+
+// minTemperature is the temperature below which annealing is considered frozen.
+const minTemperature = 1e-6
+
+// Annealing is simulated annealing over complete boards: each step proposes
+// moving a random queen to a random other column in its row, always accepts
+// moves that remove attacking pairs and accepts a move adding d pairs with
+// probability e^(-d/T). A run ends when the board is solved, the schedule
+// freezes or MaxSteps is reached, and failed runs restart up to Restarts times.
+type Annealing struct {
+	Schedule Schedule // nil uses ExponentialCooling(2, 0.999)
+	MaxSteps int      // moves proposed per run; zero means 1000·n
+	Restarts int      // random restarts allowed after a failed run
+	Seed     int64    // seed for the random number generator
+}
+
+// Solve searches for a single solution on an n×n board.
+func (a Annealing) Solve(n int) LocalResult {
+	rng := rand.New(rand.NewSource(a.Seed))
+	schedule := a.Schedule
+	if schedule == nil {
+		schedule = ExponentialCooling(2, 0.999)
+	}
+	maxSteps := a.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 1000 * n
+	}
+
+	var res LocalResult
+	for attempt := 0; attempt <= a.Restarts; attempt++ {
+		if attempt > 0 {
+			res.Restarts++
+		}
+		locs := randomBoard(n, rng)
+		l, pairs := newLineCounts(locs)
+		for step := 0; step < maxSteps && pairs > 0 && n > 1; step++ {
+			t := schedule(step)
+			if t < minTemperature {
+				break
+			}
+			r, c := rng.Intn(n), rng.Intn(n-1)
+			if c >= locs[r] {
+				c++
+			}
+			orig := locs[r]
+			delta := l.move(locs, r, c)
+			if delta > 0 && rng.Float64() >= math.Exp(-float64(delta)/t) {
+				l.move(locs, r, orig)
+			} else {
+				pairs += delta
+			}
+			res.Steps++
+		}
+		res.Locs = locs
+		if pairs == 0 {
+			res.Solved = true
+			return res
+		}
+	}
+	return res
+}
+
+// TrialStats summarizes a batch of seeded local-search runs.
+type TrialStats struct {
+	Trials          int
+	Successes       int
+	SuccessRate     float64 // fraction of trials that found a solution
+	AvgSteps        float64 // moves per trial
+	AvgStepsSuccess float64 // moves per successful trial
+	AvgStepsFailure float64 // moves per failed trial
+	AvgRestarts     float64 // restarts per trial
+}
+
+// RunTrials runs solve with seeds seed, seed+1, ..., seed+trials-1 and
+// summarizes the results, so a batch can be reproduced from its first seed.
+func RunTrials(trials int, seed int64, solve func(seed int64) LocalResult) TrialStats {
+	stats := TrialStats{Trials: trials}
+	if trials <= 0 {
+		return stats
+	}
+	var steps, stepsOK, stepsFail, restarts int
+	for i := 0; i < trials; i++ {
+		res := solve(seed + int64(i))
+		steps += res.Steps
+		restarts += res.Restarts
+		if res.Solved {
+			stats.Successes++
+			stepsOK += res.Steps
+		} else {
+			stepsFail += res.Steps
+		}
+	}
+	failures := trials - stats.Successes
+	stats.SuccessRate = float64(stats.Successes) / float64(trials)
+	stats.AvgSteps = float64(steps) / float64(trials)
+	stats.AvgRestarts = float64(restarts) / float64(trials)
+	if stats.Successes > 0 {
+		stats.AvgStepsSuccess = float64(stepsOK) / float64(stats.Successes)
+	}
+	if failures > 0 {
+		stats.AvgStepsFailure = float64(stepsFail) / float64(failures)
+	}
+	return stats
+}
@@ -0,0 +1,84 @@
+package nqueen
+
+import "testing"
+
+func TestHillClimbing(t *testing.T) {
+
+	// Textbook figures for 8-Queens: steepest ascent solves about 14% of random
+	// boards, and allowing 100 sideways moves raises that to about 94%
+	tests := []struct {
+		sideways int
+		low      float64
+		high     float64
+	}{
+		{0, 0.10, 0.20},
+		{100, 0.90, 0.98},
+	}
+	for _, tt := range tests {
+		stats := RunTrials(1000, 1, func(seed int64) LocalResult {
+			return HillClimbing{MaxSideways: tt.sideways, Seed: seed}.Solve(8)
+		})
+		if stats.SuccessRate < tt.low || stats.SuccessRate > tt.high {
+			t.Errorf("With %d sideways moves, expected a success rate in [%.2f, %.2f], but got %.3f",
+				tt.sideways, tt.low, tt.high, stats.SuccessRate)
+		}
+	}
+
+	// Random restarts make the search complete in practice
+	for seed := int64(0); seed < 20; seed++ {
+		res := HillClimbing{Restarts: 1000, Seed: seed}.Solve(8)
+		if !res.Solved || AttackingPairs(res.Locs) != 0 {
+			t.Errorf("seed %d: expected a solution with restarts, but got %+v", seed, res)
+		}
+	}
+}
+
+func TestAnnealing(t *testing.T) {
+	schedules := map[string]Schedule{
+		"exponential": ExponentialCooling(2, 0.999),
+		"linear":      LinearCooling(2, 8000),
+		"logarithmic": LogarithmicCooling(1),
+	}
+	for name, s := range schedules {
+		stats := RunTrials(50, 1, func(seed int64) LocalResult {
+			res := Annealing{Schedule: s, Restarts: 5, Seed: seed}.Solve(8)
+			if res.Solved && AttackingPairs(res.Locs) != 0 {
+				t.Errorf("%s: seed %d reported an invalid solution %v", name, seed, res.Locs)
+			}
+			return res
+		})
+		if stats.Successes != stats.Trials {
+			t.Errorf("%s: expected every trial to succeed with restarts, but got %+v", name, stats)
+		}
+	}
+
+	// 3-Queens has no solution, so every run uses its whole budget
+	res := Annealing{MaxSteps: 100, Restarts: 2}.Solve(3)
+	if res.Solved || res.Steps != 300 || res.Restarts != 2 {
+		t.Errorf("For n=3, expected 300 steps over 2 restarts without a solution, but got %+v", res)
+	}
+
+	// A linear schedule over no steps is frozen, so no move is proposed
+	for _, steps := range []int{0, -5} {
+		if temp := LinearCooling(2, steps)(0); temp != 0 {
+			t.Errorf("For %d steps, expected temperature 0, but got %v", steps, temp)
+		}
+		if res := (Annealing{Schedule: LinearCooling(2, steps), MaxSteps: 100}).Solve(8); res.Steps != 0 {
+			t.Errorf("For %d steps, expected a frozen run, but got %+v", steps, res)
+		}
+	}
+}
+
+func TestRunTrials(t *testing.T) {
+	solve := func(seed int64) LocalResult {
+		return HillClimbing{MaxSideways: 10, Seed: seed}.Solve(8)
+	}
+	a, b := RunTrials(200, 7, solve), RunTrials(200, 7, solve)
+	if a != b {
+		t.Errorf("expected identical stats for the same seeds, but got %+v and %+v", a, b)
+	}
+	want := a.AvgStepsSuccess*float64(a.Successes) + a.AvgStepsFailure*float64(a.Trials-a.Successes)
+	if diff := want - a.AvgSteps*float64(a.Trials); diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("average steps %+v do not add up", a)
+	}
+}
@@ -0,0 +1,186 @@
+package nqueen
+
+import "math/rand"
+
+// LocalResult reports the outcome of a local-search run.
+type LocalResult struct {
+	Locs     []int // final board; a solution when Solved is true
+	Solved   bool  // whether a board without conflicts was reached
+	Steps    int   // moves made across all restarts
+	Restarts int   // number of times the search started over from a new board
+}
+
+// Conflicts returns the number of pairs of queens that share a diagonal.
+// It is a linear-time equivalent of IsValid for large boards: IsValid(locs)
+// is true exactly when Conflicts(locs) is zero. Columns must be in [0, len(locs)).
+func Conflicts(locs []int) int {
+	d := newDiagonals(len(locs))
+	pairs := 0
+	for r, c := range locs {
+		pairs += d.add(r, c)
+	}
+	return pairs
+}
+
+// diagonals counts the queens on every diagonal of an n×n board.
+type diagonals struct {
+	n    int
+	sum  []int // queens on each diagonal r+c
+	diff []int // queens on each diagonal r-c+n-1
+}
+
+func newDiagonals(n int) *diagonals {
+	return &diagonals{n: n, sum: make([]int, 2*n-1), diff: make([]int, 2*n-1)}
+}
+
+// add places a queen at (r, c) and returns the number of queens it now attacks.
+func (d *diagonals) add(r, c int) int {
+	attacked := d.sum[r+c] + d.diff[r-c+d.n-1]
+	d.sum[r+c]++
+	d.diff[r-c+d.n-1]++
+	return attacked
+}
+
+// remove lifts the queen at (r, c) and returns the number of queens it was attacking.
+func (d *diagonals) remove(r, c int) int {
+	d.sum[r+c]--
+	d.diff[r-c+d.n-1]--
+	return d.sum[r+c] + d.diff[r-c+d.n-1]
+}
+
+// attacks returns the number of other queens attacking the queen at (r, c).
+func (d *diagonals) attacks(r, c int) int {
+	return d.sum[r+c] - 1 + d.diff[r-c+d.n-1] - 1
+}
+
+// swap exchanges the columns of rows i and j and returns the change in conflicting pairs.
+func (d *diagonals) swap(locs []int, i, j int) int {
+	delta := -d.remove(i, locs[i])
+	delta -= d.remove(j, locs[j])
+	locs[i], locs[j] = locs[j], locs[i]
+	delta += d.add(i, locs[i])
+	delta += d.add(j, locs[j])
+	return delta
+}
+
+// MinConflicts is a local search over permutations that repeatedly picks a
+// queen under attack and swaps it with the row that leaves the fewest
+// conflicting pairs. Swapping keeps the board a permutation, so only the
+// diagonals can conflict, and each candidate move is scored in constant time.
+// Boards larger than maxCandidates score a random sample of rows per step.
+//
+// The starting board is built greedily: each row takes a random remaining
+// column that is free of diagonal conflicts when one is found within a few
+// tries. This leaves only a handful of conflicts even for n = 10^6.
+type MinConflicts struct {
+	MaxSteps int   // moves per restart before starting over; zero means 100·n
+	Restarts int   // restarts allowed after the first attempt
+	Seed     int64 // seed for the random number generator
+}
+
+// greedyTries bounds the random columns tried per row while building the start board.
+const greedyTries = 32
+
+// maxCandidates bounds the swaps scored per step; larger boards score a random sample of rows.
+const maxCandidates = 1024
+
+// Solve searches for a single solution on an n×n board.
+func (m MinConflicts) Solve(n int) LocalResult {
+	rng := rand.New(rand.NewSource(m.Seed))
+	maxSteps := m.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 100 * n
+	}
+
+	var res LocalResult
+	for attempt := 0; attempt <= m.Restarts; attempt++ {
+		if attempt > 0 {
+			res.Restarts++
+		}
+		locs, d := greedyStart(n, rng)
+		steps, solved := minConflictsRepair(locs, d, maxSteps, rng)
+		res.Steps += steps
+		res.Locs = locs
+		if solved {
+			res.Solved = true
+			return res
+		}
+	}
+	return res
+}
+
+// greedyStart builds a random permutation, preferring columns without diagonal conflicts.
+func greedyStart(n int, rng *rand.Rand) ([]int, *diagonals) {
+	locs := rng.Perm(n)
+	d := newDiagonals(n)
+	for i := 0; i < n; i++ {
+		for try := 0; try < greedyTries; try++ {
+			j := i + rng.Intn(n-i)
+			locs[i], locs[j] = locs[j], locs[i]
+			if d.sum[i+locs[i]] == 0 && d.diff[i-locs[i]+n-1] == 0 {
+				break
+			}
+		}
+		d.add(i, locs[i])
+	}
+	return locs, d
+}
+
+// minConflictsRepair moves attacked queens until none is left or the step budget runs out.
+// return: steps taken and whether the board is a solution
+func minConflictsRepair(locs []int, d *diagonals, maxSteps int, rng *rand.Rand) (int, bool) {
+	n := len(locs)
+	// Rows that may be under attack; entries are checked lazily when drawn
+	var suspects []int
+	for r, c := range locs {
+		if d.attacks(r, c) > 0 {
+			suspects = append(suspects, r)
+		}
+	}
+
+	steps := 0
+	best := make([]int, 0, 8)
+	for steps < maxSteps {
+		if len(suspects) == 0 {
+			return steps, true
+		}
+		k := rng.Intn(len(suspects))
+		i := suspects[k]
+		if d.attacks(i, locs[i]) == 0 {
+			suspects[k] = suspects[len(suspects)-1]
+			suspects = suspects[:len(suspects)-1]
+			continue
+		}
+
+		// Score the swap with every other row (or a random sample of rows
+		// on large boards), breaking ties at random
+		bestDelta := 0
+		best = best[:0]
+		for c := 0; c < min(n, maxCandidates); c++ {
+			j := c
+			if n > maxCandidates {
+				j = rng.Intn(n)
+			}
+			if j == i {
+				continue
+			}
+			delta := d.swap(locs, i, j)
+			d.swap(locs, i, j)
+			switch {
+			case len(best) == 0 || delta < bestDelta:
+				bestDelta = delta
+				best = append(best[:0], j)
+			case delta == bestDelta:
+				best = append(best, j)
+			}
+		}
+		if len(best) == 0 {
+			break
+		}
+		j := best[rng.Intn(len(best))]
+		d.swap(locs, i, j)
+		suspects = append(suspects, j)
+		steps++
+	}
+	return steps, Conflicts(locs) == 0
+}
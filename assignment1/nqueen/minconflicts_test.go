@@ -0,0 +1,55 @@
+package nqueen
+
+import "testing"
+
+func TestMinConflicts(t *testing.T) {
+	for _, n := range []int{1, 4, 5, 8, 20, 100, 1000} {
+		res := MinConflicts{Restarts: 10, Seed: int64(n)}.Solve(n)
+		if !res.Solved {
+			t.Errorf("For n=%d, expected a solution after %d steps and %d restarts", n, res.Steps, res.Restarts)
+			continue
+		}
+		if len(res.Locs) != n || !IsValid(res.Locs) {
+			t.Errorf("For n=%d, returned board is not a solution: %v", n, res.Locs)
+		}
+		seen := make([]bool, n)
+		for _, c := range res.Locs {
+			if seen[c] {
+				t.Errorf("For n=%d, column %d is used twice", n, c)
+			}
+			seen[c] = true
+		}
+	}
+
+	// Boards without solutions exhaust the budget and report failure
+	res := MinConflicts{MaxSteps: 50, Restarts: 3}.Solve(3)
+	if res.Solved || res.Restarts != 3 {
+		t.Errorf("For n=3, expected an unsolved result after 3 restarts, but got %+v", res)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+
+	// Conflicts must agree with the pairwise check in IsValid
+	for locs := range (Permutation{}).Solutions(6) {
+		if Conflicts(locs) != 0 {
+			t.Errorf("solution %v reported %d conflicts", locs, Conflicts(locs))
+		}
+	}
+	tests := []struct {
+		locs   []int
+		expect int
+	}{
+		{[]int{0, 1, 2, 3}, 6},
+		{[]int{1, 3, 0, 2}, 0},
+		{[]int{0, 2, 1, 3}, 2},
+	}
+	for _, tt := range tests {
+		if got := Conflicts(tt.locs); got != tt.expect {
+			t.Errorf("For %v, expected %d conflicting pairs, but got %d", tt.locs, tt.expect, got)
+		}
+		if IsValid(tt.locs) != (tt.expect == 0) {
+			t.Errorf("For %v, IsValid disagrees with Conflicts", tt.locs)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package nqueen solves the n-Queens problem: place n queens on an n×n board
+// so that no two queens threaten each other.
+//
+// Boards are represented as permutations: locs[row] is the column of the queen
+// placed in that row. Rows and columns therefore never clash and only the
+// diagonals need to be checked.
+package nqueen
+
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Solver searches for solutions of the n-Queens problem.
+type Solver interface {
+	// Count returns the number of solutions on an n×n board.
+	Count(n int) int
+	// Solutions yields every solution in search order. The yielded slice is
+	// reused between iterations; copy it to keep it.
+	Solutions(n int) iter.Seq[[]int]
+	// First returns the first solution found, or false if there is none.
+	First(n int) ([]int, bool)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// onDiagonal reports whether squares (r1, c1) and (r2, c2) share a diagonal.
+func onDiagonal(r1, c1, r2, c2 int) bool {
+	return abs(r1-r2) == abs(c1-c2)
+}
+
+// IsValid checks if the board is valid (no two queens threaten each other in diagonals).
+// Returns true if valid, false otherwise.
+func IsValid(locs []int) bool {
+	for i := 0; i < len(locs); i++ {
+		for j := i + 1; j < len(locs); j++ {
+			// diagonal check: for any two queens at (i, locs[i]) and (j, locs[j]),
+			// they are on the same diagonal if abs(i-j) == abs(locs[i]-locs[j])
+			if onDiagonal(i, locs[i], j, locs[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// PrintLocs draws the board to w, one row per line, with Q marking each queen.
+// A row whose column is negative is drawn empty, so partial boards can be
+// drawn at full size.
+func PrintLocs(w io.Writer, locs []int) {
+	for _, col := range locs {
+		for j := 0; j < len(locs); j++ {
+			if j == col {
+				fmt.Fprint(w, " Q ")
+			} else {
+				fmt.Fprint(w, " . ")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintSquares draws an n×n board to w in the style of PrintLocs, with Q
+// marking each of the given squares, for boards that are not permutations.
+func PrintSquares(w io.Writer, n int, squares []Square) {
+	occupied := map[Square]bool{}
+	for _, s := range squares {
+		occupied[s] = true
+	}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			if occupied[Square{r, c}] {
+				fmt.Fprint(w, " Q ")
+			} else {
+				fmt.Fprint(w, " . ")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// first returns a copy of the first board yielded by seq.
+func first(seq iter.Seq[[]int]) ([]int, bool) {
+	for locs := range seq {
+		return append([]int(nil), locs...), true
+	}
+	return nil, false
+}
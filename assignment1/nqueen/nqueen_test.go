@@ -0,0 +1,146 @@
+package nqueen
+
+import "testing"
+
+func TestNQueensSolutions(t *testing.T) {
+
+	// Define test cases
+	// The last case with n=2 is added to test the no-solution scenario
+	tests := []struct {
+		n      int
+		expect int
+	}{
+		{4, 2},
+		{8, 92},
+		{2, 0},
+	}
+
+	for _, tt := range tests {
+		if got := (Permutation{}).Count(tt.n); got != tt.expect {
+			t.Errorf("For n=%d, expected %d solutions, but got %d", tt.n, tt.expect, got)
+		}
+	}
+}
+
+func TestNQueensBitmask(t *testing.T) {
+
+	// Known solution counts (OEIS A000170)
+	tests := []struct {
+		n      int
+		expect int
+	}{
+		{1, 1},
+		{2, 0},
+		{3, 0},
+		{4, 2},
+		{6, 4},
+		{8, 92},
+		{10, 724},
+		{12, 14200},
+	}
+
+	for _, tt := range tests {
+		if got := (Bitmask{}).Count(tt.n); got != tt.expect {
+			t.Errorf("For n=%d, expected %d solutions, but got %d", tt.n, tt.expect, got)
+		}
+	}
+
+	// Cross-check against the permutation backtracking for small boards
+	for n := 1; n <= 8; n++ {
+		want := (Permutation{}).Count(n)
+		if got := (Bitmask{}).Count(n); got != want {
+			t.Errorf("For n=%d, bitmask found %d solutions, permutation found %d", n, got, want)
+		}
+	}
+}
+
+func TestNQueensParallel(t *testing.T) {
+
+	// Different pool sizes must all agree with the sequential bitmask solver,
+	// including boards smaller than the two pre-placed rows
+	for _, workers := range []int{0, 1, 3, 16} {
+		for n := 1; n <= 10; n++ {
+			want := (Bitmask{}).Count(n)
+			if got := (Parallel{Workers: workers}).Count(n); got != want {
+				t.Errorf("For n=%d with %d workers, expected %d solutions, but got %d", n, workers, want, got)
+			}
+		}
+	}
+}
+
+func TestSolverIterators(t *testing.T) {
+
+	// Every solver must yield exactly Count valid boards and a valid First board
+	solvers := map[string]Solver{
+		"permutation": Permutation{},
+		"bitmask":     Bitmask{},
+		"parallel":    Parallel{Workers: 2},
+	}
+
+	for name, s := range solvers {
+		for n := 1; n <= 7; n++ {
+			yielded := 0
+			for locs := range s.Solutions(n) {
+				if len(locs) != n || !IsValid(locs) {
+					t.Errorf("%s: invalid board %v for n=%d", name, locs, n)
+				}
+				yielded++
+			}
+			if want := s.Count(n); yielded != want {
+				t.Errorf("%s: for n=%d, Solutions yielded %d boards, Count returned %d", name, n, yielded, want)
+			}
+
+			locs, ok := s.First(n)
+			if ok != (yielded > 0) {
+				t.Errorf("%s: for n=%d, First reported %v with %d solutions", name, n, ok, yielded)
+			}
+			if ok && !IsValid(locs) {
+				t.Errorf("%s: First returned invalid board %v for n=%d", name, locs, n)
+			}
+		}
+	}
+
+	// Stopping early must not panic or keep searching
+	seen := 0
+	for range (Bitmask{}).Solutions(8) {
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Errorf("expected to stop after 3 solutions, got %d", seen)
+	}
+}
+
+func TestSubtrees(t *testing.T) {
+
+	// The subtrees of any split add up to the full count
+	for n := 1; n <= 9; n++ {
+		want := Bitmask{}.Count(n)
+		for depth := 1; depth <= 3; depth++ {
+			for _, mirror := range []bool{false, true} {
+				got := 0
+				for _, prefix := range Subtrees(n, depth, mirror) {
+					count, err := CountSubtree(n, prefix)
+					if err != nil {
+						t.Fatalf("For n=%d, subtree %v: %v", n, prefix, err)
+					}
+					if mirror && 2*prefix[0] != n-1 {
+						count *= 2
+					}
+					got += count
+				}
+				if got != want {
+					t.Errorf("For n=%d, depth=%d, mirror=%v, expected %d solutions, but got %d", n, depth, mirror, want, got)
+				}
+			}
+		}
+	}
+
+	for _, prefix := range [][]int{{0, 1}, {8}, {-1}, {0, 2, 4, 6, 1, 3, 5, 7, 0}} {
+		if _, err := CountSubtree(8, prefix); err == nil {
+			t.Errorf("expected an error for prefix %v", prefix)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package nqueen
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"runtime"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Parallel counts with the bitmask search spread over a pool of goroutines.
+// The search tree is split on its first two rows into independent work units;
+// each worker sums the subtrees it is handed and reports a single partial
+// count, so no state is shared between goroutines.
+//
+// Solutions and First depend on search order and run the sequential Bitmask search.
+type Parallel struct {
+	// Workers is the size of the pool; zero or less uses one worker per CPU.
+	Workers int
+}
+
+// workUnit is the subtree of the bitmask search left after the first rows are placed
+type workUnit struct {
+	cols, diag1, diag2 uint64
+}
+
+// splitWork expands the first depth rows of the bitmask search into independent work units
+// input: full - mask with the lowest n bits set, depth - number of rows to pre-place,
+// prof - records the expanded nodes when not nil
+func splitWork(full uint64, depth int, unit workUnit, units []workUnit, prof *profile.Profile) []workUnit {
+	if depth == 0 || unit.cols == full {
+		return append(units, unit)
+	}
+	avail := full &^ (unit.cols | unit.diag1 | unit.diag2)
+	if prof != nil {
+		prof.Record(bits.OnesCount64(unit.cols), bits.OnesCount64(full), bits.OnesCount64(avail))
+	}
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		next := workUnit{unit.cols | bit, (unit.diag1 | bit) << 1 & full, (unit.diag2 | bit) >> 1}
+		units = splitWork(full, depth-1, next, units, prof)
+	}
+	return units
+}
+
+// Subtrees splits a count of an n×n board into independent subtrees, each
+// named by the columns of the queens in its first depth rows (all n rows if
+// depth is larger, and at least one row). Prefixes that leave no safe
+// square before depth rows are placed are dropped, as they hold no
+// solutions. With mirror set the first queen only takes the left half of
+// the row, and every subtree whose first queen is off the middle column
+// counts twice.
+func Subtrees(n, depth int, mirror bool) [][]int {
+	depth = max(depth, 1)
+	full := fullMask(n)
+	first := full
+	if mirror {
+		first = fullMask((n + 1) / 2)
+	}
+	var prefixes [][]int
+	var walk func(prefix []int, cols, diag1, diag2 uint64)
+	walk = func(prefix []int, cols, diag1, diag2 uint64) {
+		if len(prefix) == depth || cols == full {
+			prefixes = append(prefixes, append([]int(nil), prefix...))
+			return
+		}
+		avail := full &^ (cols | diag1 | diag2)
+		if len(prefix) == 0 {
+			avail &= first
+		}
+		for avail != 0 {
+			bit := avail & -avail
+			avail ^= bit
+			walk(append(prefix, bits.TrailingZeros64(bit)), cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1)
+		}
+	}
+	walk(make([]int, 0, depth), 0, 0, 0)
+	return prefixes
+}
+
+// CountSubtree returns the number of solutions on an n×n board whose first
+// rows hold queens in the columns of prefix, not counting mirror images.
+func CountSubtree(n int, prefix []int) (int, error) {
+	if n <= 0 || n > MaxBitmaskN {
+		return 0, fmt.Errorf("board size %d is outside 1..%d", n, MaxBitmaskN)
+	}
+	if len(prefix) > n {
+		return 0, fmt.Errorf("prefix %v is longer than the %d-row board", prefix, n)
+	}
+	full := fullMask(n)
+	var cols, diag1, diag2 uint64
+	for row, c := range prefix {
+		if c < 0 || c >= n {
+			return 0, fmt.Errorf("prefix %v: column %d is outside the %d×%d board", prefix, c, n, n)
+		}
+		bit := uint64(1) << c
+		if (cols|diag1|diag2)&bit != 0 {
+			return 0, fmt.Errorf("prefix %v: the queen in row %d is attacked", prefix, row)
+		}
+		cols, diag1, diag2 = cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1
+	}
+	return backtrackBitmask(full, cols, diag1, diag2, nil), nil
+}
+
+// Count returns the number of solutions on an n×n board.
+func (p Parallel) Count(n int) int {
+	return p.count(n, nil)
+}
+
+// partialCount is what one worker of Parallel found.
+type partialCount struct {
+	count int
+	prof  *profile.Profile // nil unless profiling
+}
+
+// count runs the search, recording the same tree as the sequential bitmask
+// search in prof when it is not nil: the first two rows while splitting,
+// then each work unit in a profile of its own worker.
+func (p Parallel) count(n int, prof *profile.Profile) int {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	full := fullMask(n)
+
+	// Splitting on two rows gives roughly n^2 units, enough to keep every worker busy
+	units := splitWork(full, 2, workUnit{}, nil, prof)
+
+	jobs := make(chan workUnit)
+	results := make(chan partialCount, workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			var partial partialCount
+			if prof != nil {
+				partial.prof = profile.New(n)
+			}
+			for u := range jobs {
+				partial.count += backtrackBitmask(full, u.cols, u.diag1, u.diag2, partial.prof)
+			}
+			results <- partial
+		}()
+	}
+	for _, u := range units {
+		jobs <- u
+	}
+	close(jobs)
+
+	total := 0
+	for w := 0; w < workers; w++ {
+		partial := <-results
+		total += partial.count
+		if prof != nil {
+			prof.Merge(partial.prof)
+		}
+	}
+	return total
+}
+
+// Solutions yields every solution in the order of the sequential Bitmask search.
+func (Parallel) Solutions(n int) iter.Seq[[]int] {
+	return Bitmask{}.Solutions(n)
+}
+
+// First returns the lexicographically smallest solution.
+func (Parallel) First(n int) ([]int, bool) {
+	return Bitmask{}.First(n)
+}
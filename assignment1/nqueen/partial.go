@@ -0,0 +1,206 @@
+package nqueen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+	"strings"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Completion counts and enumerates the solutions that extend a partial
+// placement. It runs the bitmask search, but each row may only use the
+// columns left open by the fixed queens: a fixed row is limited to its own
+// column, and every other row loses the fixed columns and every square a
+// fixed queen attacks before the search starts.
+// It panics for boards larger than MaxBitmaskN.
+type Completion struct {
+	Fixed map[int]int // row -> column of a pre-placed queen
+}
+
+// ParseFixed reads pre-placed queens written as "row:col" pairs separated by commas, e.g. "0:3,4:1".
+func ParseFixed(s string) (map[int]int, error) {
+	squares, err := ParseSquares(s)
+	if err != nil {
+		return nil, err
+	}
+	fixed := map[int]int{}
+	for _, sq := range squares {
+		if _, dup := fixed[sq.Row]; dup {
+			return nil, fmt.Errorf("fixed queen %d:%d: row %d already has a queen", sq.Row, sq.Col, sq.Row)
+		}
+		fixed[sq.Row] = sq.Col
+	}
+	return fixed, nil
+}
+
+// ReadBoard reads a partial placement drawn as an ASCII grid, one row per
+// line, with Q marking a queen and . an empty square; spaces are ignored,
+// so the output of PrintLocs can be read back. It returns the board size and
+// the row -> column of every queen.
+func ReadBoard(r io.Reader) (int, map[int]int, error) {
+	n, squares, err := readGrid(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	fixed := map[int]int{}
+	for _, sq := range squares {
+		if _, dup := fixed[sq.Row]; dup {
+			return 0, nil, fmt.Errorf("row %d: more than one queen", sq.Row)
+		}
+		fixed[sq.Row] = sq.Col
+	}
+	return n, fixed, nil
+}
+
+// readGrid reads a square ASCII grid as ReadBoard does, allowing any number
+// of queens per row, and returns the board size and the queens row by row.
+func readGrid(r io.Reader) (int, []Square, error) {
+	var squares []Square
+	n, width := 0, 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.ReplaceAll(sc.Text(), " ", "")
+		if line == "" {
+			continue
+		}
+		row := n
+		for c, ch := range []byte(line) {
+			switch ch {
+			case 'Q', 'q':
+				squares = append(squares, Square{row, c})
+			case '.':
+			default:
+				return 0, nil, fmt.Errorf("row %d: unexpected character %q", row, ch)
+			}
+		}
+		if n == 0 {
+			width = len(line)
+		} else if len(line) != width {
+			return 0, nil, fmt.Errorf("row %d: expected %d squares, got %d", row, width, len(line))
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return 0, nil, err
+	}
+	if width != n {
+		return 0, nil, fmt.Errorf("board has %d rows of %d squares; expected a square board", n, width)
+	}
+	return n, squares, nil
+}
+
+// Validate reports fixed queens that lie outside an n×n board.
+func (c Completion) Validate(n int) error {
+	for r, col := range c.Fixed {
+		if r < 0 || r >= n || col < 0 || col >= n {
+			return fmt.Errorf("fixed queen %d:%d is outside the %d×%d board", r, col, n, n)
+		}
+	}
+	return nil
+}
+
+// allowed returns the columns each row may use, or false when the fixed
+// queens already attack each other or lie outside the board.
+func (c Completion) allowed(n int) ([]uint64, bool) {
+	full := fullMask(n)
+	if c.Validate(n) != nil {
+		return nil, false
+	}
+	rows := make([]uint64, n)
+	for r := range rows {
+		if col, ok := c.Fixed[r]; ok {
+			rows[r] = 1 << col
+		} else {
+			rows[r] = full
+		}
+	}
+	for fr, fc := range c.Fixed {
+		for r := range rows {
+			if r == fr {
+				continue
+			}
+			// Squares of row r in the column and on the diagonals of the fixed queen
+			d := abs(r - fr)
+			attacked := uint64(1)<<fc | uint64(1)<<fc<<d&full | uint64(1)<<fc>>d
+			if col, ok := c.Fixed[r]; ok && attacked&(1<<col) != 0 {
+				return nil, false
+			}
+			rows[r] &^= attacked
+		}
+	}
+	return rows, true
+}
+
+// DFS-based backtracking restricted to the columns allowed in each row
+// prof - records every node when not nil; squares a row does not allow
+// are not counted as pruned
+// return: number of valid completions from this state
+func backtrackAllowed(rows []uint64, row int, cols, diag1, diag2 uint64, prof *profile.Profile) int {
+	if row == len(rows) {
+		if prof != nil {
+			prof.Record(row, 0, 0)
+		}
+		return 1
+	}
+	full := uint64(1)<<len(rows) - 1
+	total := 0
+	avail := rows[row] &^ (cols | diag1 | diag2)
+	if prof != nil {
+		prof.Record(row, bits.OnesCount64(rows[row]), bits.OnesCount64(avail))
+	}
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		total += backtrackAllowed(rows, row+1, cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1, prof)
+	}
+	return total
+}
+
+// Same search as backtrackAllowed, recording the column chosen in each row
+// return: false if the search was stopped
+func walkAllowed(rows []uint64, row int, cols, diag1, diag2 uint64, locs []int, yield func([]int) bool) bool {
+	if row == len(rows) {
+		return yield(locs)
+	}
+	full := uint64(1)<<len(rows) - 1
+	avail := rows[row] &^ (cols | diag1 | diag2)
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		locs[row] = bits.TrailingZeros64(bit)
+		if !walkAllowed(rows, row+1, cols|bit, (diag1|bit)<<1&full, (diag2|bit)>>1, locs, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of solutions that keep every fixed queen.
+func (c Completion) Count(n int) int {
+	rows, ok := c.allowed(n)
+	if !ok {
+		return 0
+	}
+	return backtrackAllowed(rows, 0, 0, 0, 0, nil)
+}
+
+// Solutions yields every completion in lexicographic order.
+func (c Completion) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		rows, ok := c.allowed(n)
+		if !ok {
+			return
+		}
+		walkAllowed(rows, 0, 0, 0, 0, make([]int, n), yield)
+	}
+}
+
+// First returns the lexicographically smallest completion, or false if the
+// partial placement cannot be completed.
+func (c Completion) First(n int) ([]int, bool) {
+	return first(c.Solutions(n))
+}
@@ -0,0 +1,120 @@
+package nqueen
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		n      int
+		fixed  string
+		expect int
+	}{
+		{8, "", 92},
+		{8, "0:0", 4},
+		{8, "0:0,1:4", 1},
+		{8, "0:0,1:1", 0}, // fixed queens attack each other
+		{8, "0:0,7:0", 0}, // same column
+		{4, "0:1", 1},
+		{4, "3:1", 1},
+		{4, "0:0", 0},
+	}
+
+	for _, tt := range tests {
+		fixed, err := ParseFixed(tt.fixed)
+		if err != nil {
+			t.Fatalf("ParseFixed(%q): %v", tt.fixed, err)
+		}
+		c := Completion{Fixed: fixed}
+		if got := c.Count(tt.n); got != tt.expect {
+			t.Errorf("For n=%d fixed %q, expected %d completions, but got %d", tt.n, tt.fixed, tt.expect, got)
+		}
+
+		// Every completion must be a solution that keeps the fixed queens
+		yielded := 0
+		for locs := range c.Solutions(tt.n) {
+			yielded++
+			if !IsValid(locs) {
+				t.Errorf("For n=%d fixed %q, invalid completion %v", tt.n, tt.fixed, locs)
+			}
+			for r, col := range fixed {
+				if locs[r] != col {
+					t.Errorf("For n=%d fixed %q, completion %v moved the queen of row %d", tt.n, tt.fixed, locs, r)
+				}
+			}
+		}
+		if yielded != tt.expect {
+			t.Errorf("For n=%d fixed %q, Solutions yielded %d boards, expected %d", tt.n, tt.fixed, yielded, tt.expect)
+		}
+		if _, ok := c.First(tt.n); ok != (tt.expect > 0) {
+			t.Errorf("For n=%d fixed %q, First reported completable=%v", tt.n, tt.fixed, ok)
+		}
+	}
+
+	// Fixing any full solution leaves exactly that solution
+	for locs := range (Bitmask{}).Solutions(6) {
+		fixed := map[int]int{}
+		for r, col := range locs {
+			fixed[r] = col
+		}
+		if got := (Completion{Fixed: fixed}).Count(6); got != 1 {
+			t.Errorf("fixing solution %v gave %d completions", locs, got)
+		}
+	}
+
+	// Any single fixed queen matches filtering the full solution set
+	for r := 0; r < 7; r++ {
+		for col := 0; col < 7; col++ {
+			want := 0
+			for locs := range (Bitmask{}).Solutions(7) {
+				if locs[r] == col {
+					want++
+				}
+			}
+			if got := (Completion{Fixed: map[int]int{r: col}}).Count(7); got != want {
+				t.Errorf("For n=7 fixed %d:%d, expected %d completions, but got %d", r, col, want, got)
+			}
+		}
+	}
+
+	if err := (Completion{Fixed: map[int]int{8: 0}}).Validate(8); err == nil {
+		t.Errorf("expected an error for a queen outside the board")
+	}
+}
+
+func TestParseFixed(t *testing.T) {
+	fixed, err := ParseFixed("0:3, 4:1")
+	if err != nil || !maps.Equal(fixed, map[int]int{0: 3, 4: 1}) {
+		t.Errorf("expected {0:3 4:1}, but got %v (%v)", fixed, err)
+	}
+	for _, bad := range []string{"0", "a:1", "0:b", "0:1,0:2"} {
+		if _, err := ParseFixed(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestReadBoard(t *testing.T) {
+
+	// PrintLocs output can be read back
+	var buf bytes.Buffer
+	PrintLocs(&buf, []int{1, 3, 0, 2})
+	n, fixed, err := ReadBoard(&buf)
+	if err != nil || n != 4 || !maps.Equal(fixed, map[int]int{0: 1, 1: 3, 2: 0, 3: 2}) {
+		t.Errorf("expected the 4-Queens solution, but got n=%d %v (%v)", n, fixed, err)
+	}
+
+	n, fixed, err = ReadBoard(strings.NewReader("Q...\n....\n....\n...Q\n"))
+	if err != nil || n != 4 || !maps.Equal(fixed, map[int]int{0: 0, 3: 3}) {
+		t.Errorf("expected two queens on a 4×4 board, but got n=%d %v (%v)", n, fixed, err)
+	}
+
+	for _, bad := range []string{"QQ\n..\n", "Q..\n...\n", "Q.\n.x\n"} {
+		if _, _, err := ReadBoard(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected an error for board %q", bad)
+		}
+	}
+}
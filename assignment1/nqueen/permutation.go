@@ -0,0 +1,122 @@
+package nqueen
+
+import (
+	"iter"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Permutation is the exhaustive search: it enumerates every permutation of the
+// columns and only checks the diagonals once all n queens are placed.
+// It runs in O(n!·n²) and is kept as a reference for the faster solvers.
+type Permutation struct {
+	// Mirror restricts the first queen to the left half of the board and
+	// recovers the other half by reflection, roughly halving the search.
+	Mirror bool
+}
+
+// DFS-based backtracking to place queens
+// input: locs - current board state, row - current row to place a queen
+// yield - called with each valid board; returning false stops the search
+// prof - records every node when not nil; the search never prunes a partial
+// board, and a complete board that fails IsValid counts as one pruned candidate
+// trace - called with every step when not nil; returning false stops the search
+// return: false if the search was stopped
+func backtrack(locs []int, row int, yield func([]int) bool, prof *profile.Profile, trace func(Step) bool) bool {
+	// Base case: all queen locations are swapped at least once
+	// Check if the current configuration is valid
+	if row == len(locs) {
+		n := len(locs)
+		if IsValid(locs) {
+			if prof != nil {
+				prof.Record(row, 0, 0)
+			}
+			if trace != nil && !trace(Step{Found, n - 1, locs[n-1], locs}) {
+				return false
+			}
+			return yield(locs)
+		}
+		if prof != nil {
+			prof.Record(row, 1, 0)
+		}
+		return trace == nil || trace(Step{Prune, n - 1, locs[n-1], locs})
+	}
+	if prof != nil {
+		prof.Record(row, len(locs)-row, len(locs)-row)
+	}
+
+	// Try swapping the current row with each row below it
+	for i := row; i < len(locs); i++ {
+		// Swap to place a queen at (row, locs[i])
+		locs[row], locs[i] = locs[i], locs[row]
+		// Recurse to swap queens in the next row
+		ok := tracePlace(trace, locs, row) && backtrack(locs, row+1, yield, prof, trace) && traceBacktrack(trace, locs, row)
+		// Backtrack: swap back
+		locs[row], locs[i] = locs[i], locs[row]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tracePlace reports the queen just placed in row to trace, if not nil.
+func tracePlace(trace func(Step) bool, locs []int, row int) bool {
+	return trace == nil || trace(Step{Place, row, locs[row], locs[:row+1]})
+}
+
+// traceBacktrack reports the queen in row being taken back to trace, if not nil.
+func traceBacktrack(trace func(Step) bool, locs []int, row int) bool {
+	return trace == nil || trace(Step{Backtrack, row, locs[row], locs[:row]})
+}
+
+// walk runs the search on an n×n board, recording every node in prof and
+// reporting every step to trace when they are not nil. With Mirror set, the
+// right half of the first row counts as pruned.
+func (p Permutation) walk(n int, yield func([]int) bool, prof *profile.Profile, trace func(Step) bool) {
+	locs := make([]int, n)
+	// Initialize the locs with column indices
+	for i := range locs {
+		locs[i] = i
+	}
+	if !p.Mirror {
+		backtrack(locs, 0, yield, prof, trace)
+		return
+	}
+
+	// Swapping row 0 with row i places the first queen in column i,
+	// so only the left half (and the middle column) needs to be tried
+	if prof != nil {
+		prof.Record(0, n, (n+1)/2)
+	}
+	emit := mirrorYield(n, yield)
+	for i := 0; i < (n+1)/2; i++ {
+		locs[0], locs[i] = locs[i], locs[0]
+		ok := tracePlace(trace, locs, 0) && backtrack(locs, 1, emit, prof, trace) && traceBacktrack(trace, locs, 0)
+		locs[0], locs[i] = locs[i], locs[0]
+		if !ok {
+			return
+		}
+	}
+}
+
+// Solutions yields every valid permutation of the columns.
+func (p Permutation) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		p.walk(n, yield, nil, nil)
+	}
+}
+
+// Count returns the number of solutions on an n×n board.
+func (p Permutation) Count(n int) int {
+	count := 0
+	for range p.Solutions(n) {
+		count++
+	}
+	return count
+}
+
+// First returns the first solution in permutation order.
+func (p Permutation) First(n int) ([]int, bool) {
+	return first(p.Solutions(n))
+}
@@ -0,0 +1,70 @@
+package nqueen
+
+import (
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Profiler is implemented by solvers that can profile their search tree. A
+// profile runs a full count, so Profile(n).Solutions equals Count(n).
+type Profiler interface {
+	Profile(n int) *profile.Profile
+}
+
+// Profile records the bitmask search tree. With Mirror set, the right half
+// of the first row counts as pruned and the solutions include mirror images.
+func (b Bitmask) Profile(n int) *profile.Profile {
+	start := time.Now()
+	prof := profile.New(n)
+	prof.Solutions = b.count(n, prof)
+	prof.Finish(time.Since(start))
+	return prof
+}
+
+// Profile records the same tree as the sequential bitmask search: the first
+// two rows while splitting, then each work unit on its own worker.
+func (p Parallel) Profile(n int) *profile.Profile {
+	start := time.Now()
+	prof := profile.New(n)
+	prof.Solutions = p.count(n, prof)
+	prof.Finish(time.Since(start))
+	return prof
+}
+
+// Profile records the completion search. Squares ruled out by the fixed
+// queens before the search starts are not counted as pruned.
+func (c Completion) Profile(n int) *profile.Profile {
+	start := time.Now()
+	prof := profile.New(n)
+	if rows, ok := c.allowed(n); ok {
+		prof.Solutions = backtrackAllowed(rows, 0, 0, 0, 0, prof)
+	}
+	prof.Finish(time.Since(start))
+	return prof
+}
+
+// Profile records the variant search; blocked squares are not counted as pruned.
+func (v Variant) Profile(n int) *profile.Profile {
+	start := time.Now()
+	prof := profile.New(n)
+	v.walk(n, func([]int) bool {
+		prof.Solutions++
+		return true
+	}, prof)
+	prof.Finish(time.Since(start))
+	return prof
+}
+
+// Profile records the permutation search tree. With Mirror set, the right
+// half of the first row counts as pruned and the solutions include mirror images.
+func (p Permutation) Profile(n int) *profile.Profile {
+	start := time.Now()
+	prof := profile.New(n)
+	p.walk(n, func([]int) bool {
+		prof.Solutions++
+		return true
+	}, prof, nil)
+	prof.Finish(time.Since(start))
+	return prof
+}
@@ -0,0 +1,84 @@
+package nqueen
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		want := (Bitmask{}).Count(n)
+		base := (Bitmask{}).Profile(n)
+
+		profilers := map[string]Profiler{
+			"bitmask":     Bitmask{},
+			"mirror":      Bitmask{Mirror: true},
+			"parallel":    Parallel{Workers: 3},
+			"completion":  Completion{},
+			"variant":     Variant{Model: Standard{}},
+			"permutation": Permutation{},
+			"bfs":         BFS{},
+			"iddfs":       IDDFS{},
+			"astar":       AStar{},
+		}
+		for name, p := range profilers {
+			prof := p.Profile(n)
+			if prof.Solutions != want {
+				t.Errorf("For n=%d, expected %s to profile %d solutions, but got %d", n, name, want, prof.Solutions)
+			}
+			if len(prof.Nodes) != n+1 || len(prof.Prunes) != n+1 {
+				t.Errorf("For n=%d, expected %s to record %d depths, but got %d", n, name, n+1, len(prof.Nodes))
+			}
+		}
+
+		// Splitting the work or reordering the frontier visits the same tree
+		for _, name := range []string{"parallel", "completion", "variant", "bfs", "astar"} {
+			prof := profilers[name].Profile(n)
+			if !slices.Equal(prof.Nodes, base.Nodes) || !slices.Equal(prof.Prunes, base.Prunes) || prof.Leaves != base.Leaves {
+				t.Errorf("For n=%d, expected %s to match the bitmask tree %v, but got %v", n, name, base.Nodes, prof.Nodes)
+			}
+		}
+		if base.Nodes[n] != int64(want) {
+			t.Errorf("For n=%d, expected %d complete boards, but got %d", n, want, base.Nodes[n])
+		}
+
+		// Iteration limits d..n each revisit depth d
+		iddfs := (IDDFS{}).Profile(n)
+		for d := range base.Nodes {
+			if got := iddfs.Nodes[d]; got != int64(n-d+1)*base.Nodes[d] {
+				t.Errorf("For n=%d, expected IDDFS to visit depth %d %d times, but got %d", n, d, int64(n-d+1)*base.Nodes[d], got)
+			}
+		}
+
+		// The permutation tree is never pruned before the last row
+		perm := (Permutation{}).Profile(n)
+		nodes := int64(1)
+		for d := 0; d <= n; d++ {
+			if perm.Nodes[d] != nodes {
+				t.Errorf("For n=%d, expected %d permutation nodes at depth %d, but got %d", n, nodes, d, perm.Nodes[d])
+			}
+			nodes *= int64(n - d)
+		}
+	}
+
+	// Every node at depth d considers the n squares of row d
+	prof := (Bitmask{}).Profile(6)
+	for d := 0; d < 6; d++ {
+		kept := prof.Nodes[d+1]
+		if prof.Prunes[d] != 6*prof.Nodes[d]-kept {
+			t.Errorf("depth %d: expected %d prunes, but got %d", d, 6*prof.Nodes[d]-kept, prof.Prunes[d])
+		}
+	}
+
+	data, err := json.Marshal(prof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"nodes_per_depth", "prunes_per_depth", "leaves", "wall_seconds", "solutions_per_second"} {
+		if !strings.Contains(string(data), `"`+key+`"`) {
+			t.Errorf("expected key %q in %s", key, data)
+		}
+	}
+}
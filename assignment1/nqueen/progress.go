@@ -0,0 +1,187 @@
+package nqueen
+
+import (
+	"context"
+	"math/bits"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Progress describes how far a cancellable count has got. The search is
+// split into first-row branches, one per column of the first queen, and
+// each branch into subtrees on its second row; progress advances as
+// subtrees finish.
+type Progress struct {
+	Count        int // solutions in the finished subtrees
+	BranchesDone int // first-row branches whose subtrees have all finished
+	Branches     int
+	UnitsDone    int // finished subtrees, including those resumed
+	Units        int
+	Resumed      int // subtrees taken from a checkpoint rather than searched
+	Elapsed      time.Duration
+}
+
+// Fraction returns the share of subtrees finished, between 0 and 1.
+func (p Progress) Fraction() float64 {
+	if p.Units == 0 {
+		return 1
+	}
+	return float64(p.UnitsDone) / float64(p.Units)
+}
+
+// ETA estimates the time left, assuming the remaining subtrees take as long
+// on average as those searched so far. It is zero until a subtree finishes.
+func (p Progress) ETA() time.Duration {
+	searched := p.UnitsDone - p.Resumed
+	if searched <= 0 {
+		return 0
+	}
+	return time.Duration(float64(p.Elapsed) * float64(p.Units-p.UnitsDone) / float64(searched))
+}
+
+// ContextCounter is implemented by solvers whose counts can be cancelled.
+// CountContext returns the full count and a nil error, or, once ctx is done,
+// the solutions found in the subtrees that finished and ctx.Err() to mark the
+// count incomplete. progress, if not nil, is called after each subtree.
+type ContextCounter interface {
+	CountContext(ctx context.Context, n int, progress func(Progress)) (int, error)
+}
+
+// CountContext counts like Count, checking ctx between subtrees.
+func (b Bitmask) CountContext(ctx context.Context, n int, progress func(Progress)) (int, error) {
+	return countContext(ctx, NewCheckpoint(n, b.Mirror), 1, progress)
+}
+
+// CountContext counts like Count, checking ctx between subtrees.
+func (p Parallel) CountContext(ctx context.Context, n int, progress func(Progress)) (int, error) {
+	return countContext(ctx, NewCheckpoint(n, false), p.Workers, progress)
+}
+
+// branchUnit is a work unit of the first-row branch it belongs to, named
+// by the columns of its first two queens.
+type branchUnit struct {
+	prefix [2]int
+	work   workUnit
+}
+
+// branchCount is the count of one finished unit.
+type branchCount struct {
+	prefix [2]int
+	count  int
+}
+
+// countContext runs the bitmask count of cp.N on a pool of workers, one
+// subtree per first two rows at a time, and stops handing out subtrees once
+// ctx is done. Subtrees already in cp are not searched again, and each
+// subtree that finishes is added to cp before progress is called. With
+// cp.Mirror set only the left half of the first row is searched and counted
+// twice, as in Bitmask.Count.
+func countContext(ctx context.Context, cp *Checkpoint, workers int, progress func(Progress)) (int, error) {
+	start := time.Now()
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	n := cp.N
+	full := fullMask(n)
+	branches := n
+	if cp.Mirror {
+		branches = (n + 1) / 2
+	}
+	// Every solution with the first queen off the middle column has a mirror image
+	weight := func(branch int) int {
+		if cp.Mirror && 2*branch != n-1 {
+			return 2
+		}
+		return 1
+	}
+
+	var units []branchUnit
+	remaining := make([]int, branches)
+	for col := 0; col < branches; col++ {
+		bit := uint64(1) << col
+		for _, u := range splitWork(full, 1, workUnit{bit, bit << 1 & full, bit >> 1}, nil, nil) {
+			second := -1
+			if rest := u.cols &^ bit; rest != 0 {
+				second = bits.TrailingZeros64(rest)
+			}
+			units = append(units, branchUnit{[2]int{col, second}, u})
+			remaining[col]++
+		}
+	}
+
+	p := Progress{Branches: branches, Units: len(units)}
+	done, err := cp.done(units)
+	if err != nil {
+		return 0, err
+	}
+	todo := units[:0:0]
+	for _, u := range units {
+		count, ok := done[u.prefix]
+		if !ok {
+			todo = append(todo, u)
+			continue
+		}
+		p.Count += weight(u.prefix[0]) * count
+		p.UnitsDone++
+		p.Resumed++
+		remaining[u.prefix[0]]--
+	}
+	for _, r := range remaining {
+		// A branch is done once its subtrees are, or at once if the first
+		// queen leaves no safe square in the second row
+		if r == 0 {
+			p.BranchesDone++
+		}
+	}
+
+	jobs := make(chan branchUnit)
+	results := make(chan branchCount)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				results <- branchCount{u.prefix, backtrackBitmask(full, u.work.cols, u.work.diag1, u.work.diag2, nil)}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, u := range todo {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		cp.add(r.prefix, r.count)
+		p.Count += weight(r.prefix[0]) * r.count
+		p.UnitsDone++
+		if remaining[r.prefix[0]]--; remaining[r.prefix[0]] == 0 {
+			p.BranchesDone++
+		}
+		p.Elapsed = time.Since(start)
+		if progress != nil {
+			progress(p)
+		}
+	}
+	if p.UnitsDone < p.Units {
+		return p.Count, ctx.Err()
+	}
+	return p.Count, nil
+}
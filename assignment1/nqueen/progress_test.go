@@ -0,0 +1,62 @@
+package nqueen
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCountContext(t *testing.T) {
+	counters := map[string]ContextCounter{
+		"bitmask":  Bitmask{},
+		"mirror":   Bitmask{Mirror: true},
+		"parallel": Parallel{Workers: 3},
+	}
+	for name, c := range counters {
+		for n := 1; n <= 10; n++ {
+			want := (Bitmask{}).Count(n)
+			var last Progress
+			got, err := c.CountContext(context.Background(), n, func(p Progress) { last = p })
+			if err != nil || got != want {
+				t.Errorf("For n=%d, expected %s to count %d solutions, but got %d (%v)", n, name, want, got, err)
+			}
+			if last.Count != want || last.BranchesDone != last.Branches || last.UnitsDone != last.Units || last.Fraction() != 1 {
+				t.Errorf("For n=%d, expected %s to finish with full progress, but got %+v", n, name, last)
+			}
+		}
+	}
+
+	// A cancelled count stops early and keeps what it found
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen []Progress
+	got, err := (Bitmask{}).CountContext(ctx, 12, func(p Progress) {
+		seen = append(seen, p)
+		if p.BranchesDone == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, but got %v", err)
+	}
+	last := seen[len(seen)-1]
+	if got != last.Count || last.UnitsDone == last.Units || got >= 14200 {
+		t.Errorf("expected a partial count, but got %d after %+v", got, last)
+	}
+
+	// A deadline that has already passed finds nothing
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	if got, err := (Parallel{Workers: 2}).CountContext(ctx, 12, nil); err != context.DeadlineExceeded || got != 0 {
+		t.Errorf("expected no solutions and DeadlineExceeded, but got %d, %v", got, err)
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	p := Progress{UnitsDone: 1, Units: 4, Elapsed: time.Second}
+	if p.Fraction() != 0.25 || p.ETA() != 3*time.Second {
+		t.Errorf("expected 25%% done with 3s left, but got %v and %v", p.Fraction(), p.ETA())
+	}
+	if (Progress{Units: 4}).ETA() != 0 {
+		t.Errorf("expected no estimate before any subtree finishes")
+	}
+}
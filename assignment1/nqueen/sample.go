@@ -0,0 +1,226 @@
+package nqueen
+
+import (
+	"iter"
+	"math/bits"
+	"math/rand"
+)
+
+// Sampler draws random solutions.
+type Sampler interface {
+	// Samples yields k random solutions of an n×n board, fewer if it has
+	// none. The yielded slice is reused between iterations; copy it to keep it.
+	Samples(n, k int) iter.Seq[[]int]
+}
+
+// UniformSampler draws solutions uniformly at random. It places the queens
+// row by row, picking each column with probability proportional to the
+// number of solutions below it, which it counts with the bitmask search.
+// The first sample costs about a full count; the counts of the top rows are
+// kept for the samples that follow. It panics for boards larger than MaxBitmaskN.
+type UniformSampler struct {
+	Seed int64 // seed for the random number generator
+}
+
+// memoRows is how many rows of subtree counts a UniformSampler keeps.
+const memoRows = 3
+
+// subtreeCounts counts the solutions below bitmask search states, keeping
+// the counts of states in the first memoRows rows.
+type subtreeCounts struct {
+	full uint64
+	memo map[workUnit]int
+}
+
+func (s *subtreeCounts) count(u workUnit, row int) int {
+	if row >= memoRows || u.cols == s.full {
+		return backtrackBitmask(s.full, u.cols, u.diag1, u.diag2, nil)
+	}
+	if count, ok := s.memo[u]; ok {
+		return count
+	}
+	// Sum the children so each kept count is searched only once
+	count := 0
+	avail := s.full &^ (u.cols | u.diag1 | u.diag2)
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		count += s.count(workUnit{u.cols | bit, (u.diag1 | bit) << 1 & s.full, (u.diag2 | bit) >> 1}, row+1)
+	}
+	s.memo[u] = count
+	return count
+}
+
+// Samples yields k solutions, each drawn independently and uniformly.
+func (s UniformSampler) Samples(n, k int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		rng := rand.New(rand.NewSource(s.Seed))
+		counts := &subtreeCounts{full: fullMask(n), memo: map[workUnit]int{}}
+		if counts.count(workUnit{}, 0) == 0 {
+			return
+		}
+		locs := make([]int, n)
+		for i := 0; i < k; i++ {
+			sampleUniform(counts, locs, rng)
+			if !yield(locs) {
+				return
+			}
+		}
+	}
+}
+
+// sampleUniform fills locs with a uniformly random solution; the board must have one.
+func sampleUniform(counts *subtreeCounts, locs []int, rng *rand.Rand) {
+	full := counts.full
+	var u workUnit
+	for row := range locs {
+		// Walk the children in search order until the random rank falls
+		// inside one of their subtrees
+		rank := rng.Intn(counts.count(u, row))
+		avail := full &^ (u.cols | u.diag1 | u.diag2)
+		for avail != 0 {
+			bit := avail & -avail
+			avail ^= bit
+			child := workUnit{u.cols | bit, (u.diag1 | bit) << 1 & full, (u.diag2 | bit) >> 1}
+			below := counts.count(child, row+1)
+			if rank < below {
+				locs[row] = bits.TrailingZeros64(bit)
+				u = child
+				break
+			}
+			rank -= below
+		}
+	}
+}
+
+// LasVegasSampler draws solutions of boards of any size with randomized
+// restarts: it places a queen on a random safe column of each row in turn,
+// completes the last Tail rows with a backtracking search that tries the safe
+// columns in random order, and starts over whenever either gets stuck. Every
+// board it returns is a solution, but the draw is not uniform: solutions
+// reached through rows with fewer safe columns come up more often.
+type LasVegasSampler struct {
+	Tail int   // rows completed by backtracking; zero or less uses 16
+	Seed int64 // seed for the random number generator
+}
+
+// LasVegasStats describes the work of a Las Vegas sample.
+type LasVegasStats struct {
+	Restarts int // attempts that got stuck before the sample was found
+}
+
+// lasVegasBoard tracks the queens placed on the columns and diagonals.
+type lasVegasBoard struct {
+	locs       []int
+	col        []bool
+	sum, diff  []bool // diagonals r+c and r-c+n-1
+	candidates [][]int
+}
+
+func newLasVegasBoard(n int) *lasVegasBoard {
+	return &lasVegasBoard{
+		locs:       make([]int, n),
+		col:        make([]bool, n),
+		sum:        make([]bool, 2*n),
+		diff:       make([]bool, 2*n),
+		candidates: make([][]int, n),
+	}
+}
+
+func (b *lasVegasBoard) safe(r, c int) bool {
+	return !b.col[c] && !b.sum[r+c] && !b.diff[r-c+len(b.locs)-1]
+}
+
+func (b *lasVegasBoard) set(r, c int, placed bool) {
+	b.col[c], b.sum[r+c], b.diff[r-c+len(b.locs)-1] = placed, placed, placed
+	b.locs[r] = c
+}
+
+func (b *lasVegasBoard) clear() {
+	clear(b.col)
+	clear(b.sum)
+	clear(b.diff)
+}
+
+// DFS-based completion of the rows from row on, trying safe columns in random order
+// return: true once every row holds a queen
+func (b *lasVegasBoard) complete(row int, rng *rand.Rand) bool {
+	n := len(b.locs)
+	if row == n {
+		return true
+	}
+	cands := b.candidates[row][:0]
+	for c := 0; c < n; c++ {
+		if b.safe(row, c) {
+			cands = append(cands, c)
+		}
+	}
+	b.candidates[row] = cands
+	rng.Shuffle(len(cands), func(i, j int) { cands[i], cands[j] = cands[j], cands[i] })
+	for _, c := range cands {
+		b.set(row, c, true)
+		if b.complete(row+1, rng) {
+			return true
+		}
+		b.set(row, c, false)
+	}
+	return false
+}
+
+// sample fills b.locs with a solution and returns the restarts it took.
+func (l LasVegasSampler) sample(b *lasVegasBoard, rng *rand.Rand) int {
+	n := len(b.locs)
+	tail := l.Tail
+	if tail <= 0 {
+		tail = 16
+	}
+	tail = min(tail, n)
+	for restarts := 0; ; restarts++ {
+		b.clear()
+		stuck := false
+		for row := 0; row < n-tail; row++ {
+			// Pick uniformly among the safe columns by reservoir sampling
+			pick, safe := -1, 0
+			for c := 0; c < n; c++ {
+				if b.safe(row, c) {
+					safe++
+					if rng.Intn(safe) == 0 {
+						pick = c
+					}
+				}
+			}
+			if pick < 0 {
+				stuck = true
+				break
+			}
+			b.set(row, pick, true)
+		}
+		if !stuck && b.complete(n-tail, rng) {
+			return restarts
+		}
+	}
+}
+
+// Samples yields k solutions. Boards of size 2 and 3 have none and yield nothing.
+func (l LasVegasSampler) Samples(n, k int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		l.SamplesWithStats(n, k, func(locs []int, _ LasVegasStats) bool {
+			return yield(locs)
+		})
+	}
+}
+
+// SamplesWithStats draws k solutions like Samples, passing the work each took to yield.
+func (l LasVegasSampler) SamplesWithStats(n, k int, yield func([]int, LasVegasStats) bool) {
+	if n <= 0 || n == 2 || n == 3 {
+		return
+	}
+	rng := rand.New(rand.NewSource(l.Seed))
+	b := newLasVegasBoard(n)
+	for i := 0; i < k; i++ {
+		restarts := l.sample(b, rng)
+		if !yield(b.locs, LasVegasStats{Restarts: restarts}) {
+			return
+		}
+	}
+}
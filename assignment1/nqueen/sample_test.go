@@ -0,0 +1,87 @@
+package nqueen
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUniformSampler(t *testing.T) {
+
+	// Every solution of the 8×8 board comes up about equally often
+	const perSolution = 200
+	seen := map[string]int{}
+	for locs := range (UniformSampler{Seed: 7}).Samples(8, 92*perSolution) {
+		if !IsValid(locs) {
+			t.Fatalf("sampled invalid board %v", locs)
+		}
+		seen[fmt.Sprint(locs)]++
+	}
+	if len(seen) != 92 {
+		t.Errorf("expected all 92 solutions, but got %d", len(seen))
+	}
+	for board, count := range seen {
+		// Five standard deviations of a binomial count
+		if count < perSolution-70 || count > perSolution+70 {
+			t.Errorf("expected about %d draws of %s, but got %d", perSolution, board, count)
+		}
+	}
+
+	for _, n := range []int{2, 3} {
+		for range (UniformSampler{}).Samples(n, 5) {
+			t.Errorf("For n=%d, expected no samples", n)
+		}
+	}
+}
+
+func TestLasVegasSampler(t *testing.T) {
+	tests := []struct {
+		n, tail int
+	}{
+		{1, 0},
+		{4, 0},
+		{8, 3},
+		{30, 0},
+		{200, 12},
+	}
+	for _, tt := range tests {
+		got := 0
+		for locs := range (LasVegasSampler{Tail: tt.tail, Seed: 3}).Samples(tt.n, 5) {
+			if len(locs) != tt.n || !IsValid(locs) || AttackingPairs(locs) != 0 {
+				t.Errorf("For n=%d, sampled invalid board %v", tt.n, locs)
+			}
+			got++
+		}
+		if got != 5 {
+			t.Errorf("For n=%d, expected 5 samples, but got %d", tt.n, got)
+		}
+	}
+	for range (LasVegasSampler{}).Samples(3, 5) {
+		t.Errorf("For n=3, expected no samples")
+	}
+
+	// The stats come with the same boards Samples draws. Backtracking the
+	// whole board never restarts, while placing all but one row at random
+	// on a large board gets stuck often.
+	for _, tt := range []struct {
+		tail     int
+		restarts bool
+	}{{40, false}, {1, true}} {
+		l := LasVegasSampler{Tail: tt.tail, Seed: 5}
+		var boards []string
+		for locs := range l.Samples(40, 5) {
+			boards = append(boards, fmt.Sprint(locs))
+		}
+		i, restarts := 0, 0
+		l.SamplesWithStats(40, 5, func(locs []int, stats LasVegasStats) bool {
+			if i >= len(boards) || fmt.Sprint(locs) != boards[i] {
+				t.Errorf("For tail=%d, sample %d differs from Samples: %v", tt.tail, i, locs)
+			}
+			i++
+			restarts += stats.Restarts
+			return true
+		})
+		if i != 5 || (restarts > 0) != tt.restarts {
+			t.Errorf("For tail=%d, expected 5 samples with restarts=%v, but got %d samples and %d restarts", tt.tail, tt.restarts, i, restarts)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package nqueen
+
+import (
+	"math/bits"
+	"time"
+	"unsafe"
+)
+
+// SearchStats reports the work done by a search with an explicit frontier.
+type SearchStats struct {
+	Solutions   int           // solutions found
+	Expanded    int           // nodes taken off the frontier
+	Generated   int           // nodes added to the frontier
+	MaxFrontier int           // largest number of nodes held on the frontier at once
+	Elapsed     time.Duration // wall time of the search
+
+	// MaxFrontierBytes is the largest memory held by frontier boards at once
+	MaxFrontierBytes int
+}
+
+// track records a frontier of size nodes holding the given bytes.
+func (s *SearchStats) track(nodes, bytes int) {
+	s.MaxFrontier = max(s.MaxFrontier, nodes)
+	s.MaxFrontierBytes = max(s.MaxFrontierBytes, bytes)
+}
+
+// Partial is a board with queens placed in its first len(Locs) rows.
+type Partial struct {
+	N    int
+	Locs []int // columns of the queens placed so far
+
+	// Occupied columns and attacked diagonals as seen from the next row,
+	// for this board and for the board before the last queen was placed
+	cols, diag1, diag2    uint64
+	pcols, pdiag1, pdiag2 uint64
+}
+
+// Row returns the next row to fill.
+func (p Partial) Row() int {
+	return len(p.Locs)
+}
+
+// free returns the mask of safe columns in row r (r >= Row) under the given masks.
+func free(n, dist int, cols, diag1, diag2 uint64) uint64 {
+	full := uint64(1)<<n - 1
+	return full &^ (cols | diag1<<dist | diag2>>dist)
+}
+
+// Free returns the number of safe squares left in row r (r >= Row).
+func (p Partial) Free(r int) int {
+	return bits.OnesCount64(free(p.N, r-p.Row(), p.cols, p.diag1, p.diag2))
+}
+
+// children returns the boards obtained by placing a queen on each safe column of the next row.
+func children(p Partial) []Partial {
+	full := fullMask(p.N)
+	var out []Partial
+	avail := full &^ (p.cols | p.diag1 | p.diag2)
+	for avail != 0 {
+		bit := avail & -avail
+		avail ^= bit
+		locs := make([]int, len(p.Locs)+1)
+		copy(locs, p.Locs)
+		locs[len(p.Locs)] = bits.TrailingZeros64(bit)
+		out = append(out, Partial{
+			N:      p.N,
+			Locs:   locs,
+			cols:   p.cols | bit,
+			diag1:  (p.diag1 | bit) << 1 & full,
+			diag2:  (p.diag2 | bit) >> 1,
+			pcols:  p.cols,
+			pdiag1: p.diag1,
+			pdiag2: p.diag2,
+		})
+	}
+	return out
+}
+
+// sizeOf returns the memory held by a frontier board: the struct and its row slice.
+func sizeOf(p Partial) int {
+	return int(unsafe.Sizeof(p)) + cap(p.Locs)*int(unsafe.Sizeof(0))
+}
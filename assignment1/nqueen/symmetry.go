@@ -0,0 +1,125 @@
+package nqueen
+
+import "slices"
+
+// NumSymmetries is the number of symmetries of the square board:
+// four rotations, each with or without a reflection.
+const NumSymmetries = 8
+
+// transform writes into dst the board obtained by applying symmetry k (0..7) to locs.
+// The queen at (r, c) moves to:
+// 0: (r, c)  1: (c, n-1-r)  2: (n-1-r, n-1-c)  3: (n-1-c, r)
+// 4: (r, n-1-c)  5: (n-1-r, c)  6: (c, r)  7: (n-1-c, n-1-r)
+func transform(dst, locs []int, k int) {
+	n := len(locs)
+	for r, c := range locs {
+		switch k {
+		case 0:
+			dst[r] = c
+		case 1:
+			dst[c] = n - 1 - r
+		case 2:
+			dst[n-1-r] = n - 1 - c
+		case 3:
+			dst[n-1-c] = r
+		case 4:
+			dst[r] = n - 1 - c
+		case 5:
+			dst[n-1-r] = c
+		case 6:
+			dst[c] = r
+		case 7:
+			dst[n-1-c] = n - 1 - r
+		}
+	}
+}
+
+// Symmetries returns the 8 images of the board under rotations and reflections,
+// starting with the board itself. Images may repeat for symmetric boards.
+func Symmetries(locs []int) [][]int {
+	images := make([][]int, NumSymmetries)
+	for k := range images {
+		images[k] = make([]int, len(locs))
+		transform(images[k], locs, k)
+	}
+	return images
+}
+
+// Canonical returns the lexicographically smallest image of the board,
+// which identifies its equivalence class under the board symmetries.
+func Canonical(locs []int) []int {
+	best := slices.Clone(locs)
+	img := make([]int, len(locs))
+	for k := 1; k < NumSymmetries; k++ {
+		transform(img, locs, k)
+		if slices.Compare(img, best) < 0 {
+			copy(best, img)
+		}
+	}
+	return best
+}
+
+// OrbitSize returns the number of distinct boards among the images of locs.
+// It is 8 divided by the number of symmetries that leave the board unchanged.
+func OrbitSize(locs []int) int {
+	fixed := 0
+	img := make([]int, len(locs))
+	for k := 0; k < NumSymmetries; k++ {
+		transform(img, locs, k)
+		if slices.Equal(img, locs) {
+			fixed++
+		}
+	}
+	return NumSymmetries / fixed
+}
+
+// SymmetryReport summarizes the solutions of one board size under the board symmetries.
+type SymmetryReport struct {
+	Total       int         // all solutions
+	Fundamental int         // solutions distinct up to rotation and reflection
+	Orbits      map[int]int // orbit size -> number of fundamental solutions with that orbit
+}
+
+// ReduceSymmetry groups the solutions found by s into equivalence classes.
+// A solution is counted as fundamental when it is its own canonical image,
+// so no set of seen solutions has to be kept in memory.
+func ReduceSymmetry(s Solver, n int) SymmetryReport {
+	report := SymmetryReport{Orbits: map[int]int{}}
+	img := make([]int, n)
+	for locs := range s.Solutions(n) {
+		report.Total++
+		canonical, fixed := true, 0
+		for k := 0; k < NumSymmetries; k++ {
+			transform(img, locs, k)
+			switch slices.Compare(img, locs) {
+			case -1:
+				canonical = false
+			case 0:
+				fixed++
+			}
+		}
+		if canonical {
+			report.Fundamental++
+			report.Orbits[NumSymmetries/fixed]++
+		}
+	}
+	return report
+}
+
+// mirrorYield wraps yield so that every board is followed by its left-right mirror image.
+// Searches that restrict the first queen to the left half of the board use it to
+// recover the full solution set. Boards whose first queen sits on the middle column
+// of an odd board are their own class and are yielded once.
+func mirrorYield(n int, yield func([]int) bool) func([]int) bool {
+	mirrored := make([]int, n)
+	return func(locs []int) bool {
+		if !yield(locs) {
+			return false
+		}
+		if 2*locs[0] == n-1 {
+			return true
+		}
+		transform(mirrored, locs, 4)
+		return yield(mirrored)
+	}
+}
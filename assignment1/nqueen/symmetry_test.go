@@ -0,0 +1,94 @@
+package nqueen
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReduceSymmetry(t *testing.T) {
+
+	// Fundamental solution counts (OEIS A002562) and their orbit sizes
+	tests := []struct {
+		n           int
+		total       int
+		fundamental int
+		orbits      map[int]int
+	}{
+		{1, 1, 1, map[int]int{1: 1}},
+		{4, 2, 1, map[int]int{2: 1}},
+		{5, 10, 2, map[int]int{2: 1, 8: 1}},
+		{6, 4, 1, map[int]int{4: 1}},
+		{8, 92, 12, map[int]int{4: 1, 8: 11}},
+		{10, 724, 92, map[int]int{4: 3, 8: 89}},
+	}
+
+	for _, tt := range tests {
+		r := ReduceSymmetry(Bitmask{}, tt.n)
+		if r.Total != tt.total || r.Fundamental != tt.fundamental {
+			t.Errorf("For n=%d, expected %d/%d total/fundamental, but got %d/%d",
+				tt.n, tt.total, tt.fundamental, r.Total, r.Fundamental)
+		}
+		for size, want := range tt.orbits {
+			if r.Orbits[size] != want {
+				t.Errorf("For n=%d, expected %d orbits of size %d, but got %d", tt.n, want, size, r.Orbits[size])
+			}
+		}
+	}
+}
+
+func TestSymmetries(t *testing.T) {
+	locs := []int{0, 4, 7, 5, 2, 6, 1, 3}
+	canonical := Canonical(locs)
+	for _, img := range Symmetries(locs) {
+		if !IsValid(img) {
+			t.Errorf("image %v of a valid board is invalid", img)
+		}
+		if !slices.Equal(Canonical(img), canonical) {
+			t.Errorf("image %v has canonical form %v, expected %v", img, Canonical(img), canonical)
+		}
+	}
+	if got := OrbitSize(locs); got != 8 {
+		t.Errorf("expected orbit size 8, but got %d", got)
+	}
+	if got := OrbitSize([]int{1, 3, 0, 2}); got != 2 {
+		t.Errorf("expected orbit size 2 for the 4-Queens solution, but got %d", got)
+	}
+}
+
+func TestMirror(t *testing.T) {
+
+	// The mirrored searches must find exactly the same set of solutions
+	for n := 1; n <= 9; n++ {
+		want := map[string]bool{}
+		for locs := range (Bitmask{}).Solutions(n) {
+			want[boardKey(locs)] = true
+		}
+		for name, s := range map[string]Solver{
+			"permutation": Permutation{Mirror: true},
+			"bitmask":     Bitmask{Mirror: true},
+		} {
+			got := map[string]bool{}
+			for locs := range s.Solutions(n) {
+				got[boardKey(locs)] = true
+			}
+			if len(got) != len(want) || s.Count(n) != len(want) {
+				t.Errorf("%s: for n=%d, expected %d solutions, but got %d distinct and Count %d",
+					name, n, len(want), len(got), s.Count(n))
+			}
+			for k := range got {
+				if !want[k] {
+					t.Errorf("%s: for n=%d, unexpected solution %v", name, n, []byte(k))
+				}
+			}
+		}
+	}
+}
+
+// boardKey packs a small board into a comparable map key.
+func boardKey(locs []int) string {
+	key := make([]byte, len(locs))
+	for i, c := range locs {
+		key[i] = byte(c)
+	}
+	return string(key)
+}
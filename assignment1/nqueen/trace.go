@@ -0,0 +1,71 @@
+package nqueen
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Event is what happened at one step of a traced search.
+type Event int
+
+const (
+	// Place puts a queen on (Row, Col).
+	Place Event = iota
+	// Backtrack takes the queen in Row back off (Row, Col).
+	Backtrack
+	// Prune rejects the board; for the permutation search, a complete
+	// board with two queens on a diagonal.
+	Prune
+	// Found reports a solution.
+	Found
+)
+
+var eventNames = []string{"place", "backtrack", "prune", "solution"}
+
+func (e Event) String() string {
+	if e < 0 || int(e) >= len(eventNames) {
+		return fmt.Sprintf("Event(%d)", int(e))
+	}
+	return eventNames[e]
+}
+
+// MarshalText writes the event by name, as in event logs.
+func (e Event) MarshalText() ([]byte, error) {
+	if e < 0 || int(e) >= len(eventNames) {
+		return nil, fmt.Errorf("unknown event %d", int(e))
+	}
+	return []byte(eventNames[e]), nil
+}
+
+// UnmarshalText reads an event written by MarshalText.
+func (e *Event) UnmarshalText(text []byte) error {
+	for i, name := range eventNames {
+		if string(text) == name {
+			*e = Event(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event %q", text)
+}
+
+// Step is one event of a traced search. Board holds the columns of the
+// queens placed so far, row by row from the top, after the event; it is
+// reused between steps, so copy it to keep it.
+type Step struct {
+	Event Event `json:"event"`
+	Row   int   `json:"row"`
+	Col   int   `json:"col"`
+	Board []int `json:"board"`
+}
+
+// Trace yields every step of the permutation search that Solutions runs:
+// each queen placed and taken back, and each complete board found or
+// pruned. With Mirror set the first queen only tries the left half of the row.
+func (p Permutation) Trace(n int) iter.Seq[Step] {
+	return func(yield func(Step) bool) {
+		if n <= 0 {
+			return
+		}
+		p.walk(n, func([]int) bool { return true }, nil, yield)
+	}
+}
@@ -0,0 +1,65 @@
+package nqueen
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	for n := 1; n <= 6; n++ {
+		for _, mirror := range []bool{false, true} {
+			p := Permutation{Mirror: mirror}
+			events := map[Event]int{}
+			var found [][]int
+			depth := 0
+			for s := range p.Trace(n) {
+				events[s.Event]++
+				switch s.Event {
+				case Place:
+					depth++
+				case Backtrack:
+					depth--
+				case Found:
+					if !IsValid(s.Board) {
+						t.Errorf("For n=%d, traced invalid solution %v", n, s.Board)
+					}
+					found = append(found, slices.Clone(s.Board))
+				}
+				if len(s.Board) != depth {
+					t.Errorf("For n=%d, expected %d queens after %v, but got %v", n, depth, s.Event, s.Board)
+				}
+			}
+
+			// Every placement is taken back, and every complete board is
+			// either a solution in search order or pruned
+			var want [][]int
+			leaves := factorial(n)
+			if mirror {
+				leaves = (n + 1) / 2 * factorial(n-1)
+				for locs := range (Permutation{}).Solutions(n) {
+					if 2*locs[0] < n {
+						want = append(want, slices.Clone(locs))
+					}
+				}
+			} else {
+				for locs := range p.Solutions(n) {
+					want = append(want, slices.Clone(locs))
+				}
+			}
+			if events[Place] != events[Backtrack] || events[Found]+events[Prune] != leaves {
+				t.Errorf("For n=%d, mirror=%v, expected balanced placements and %d complete boards, but got %v", n, mirror, leaves, events)
+			}
+			if !slices.EqualFunc(found, want, slices.Equal) {
+				t.Errorf("For n=%d, mirror=%v, expected solutions %v, but got %v", n, mirror, want, found)
+			}
+		}
+	}
+}
+
+func factorial(n int) int {
+	f := 1
+	for i := 2; i <= n; i++ {
+		f *= i
+	}
+	return f
+}
@@ -0,0 +1,199 @@
+package nqueen
+
+import (
+	"iter"
+	"time"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// BFS is a breadth-first search over partial placements. The frontier is a
+// FIFO queue, so every board with k queens is generated before any board
+// with k+1 queens and the queue grows to hold a whole level of the tree.
+// It panics for boards larger than MaxBitmaskN.
+type BFS struct{}
+
+// Search runs the breadth-first search on an n×n board, calling yield with
+// each solution; returning false from yield stops the search. A nil yield
+// counts every solution.
+func (b BFS) Search(n int, yield func([]int) bool) SearchStats {
+	return b.search(n, yield, nil)
+}
+
+// search runs the breadth-first search, recording every node in prof when it is not nil.
+func (BFS) search(n int, yield func([]int) bool, prof *profile.Profile) SearchStats {
+	start := time.Now()
+	fullMask(n)
+
+	var stats SearchStats
+	// The queue is consumed from head. Consumed slots are cleared so their
+	// boards can be collected, and once more than half the queue is consumed
+	// the rest is moved to the front, reusing the backing array instead of
+	// growing it; the array itself is never shrunk
+	queue := []Partial{{N: n}}
+	head, bytes := 0, sizeOf(queue[0])
+	stats.Generated = 1
+	stats.track(1, bytes)
+	for head < len(queue) {
+		p := queue[head]
+		queue[head] = Partial{}
+		head++
+		bytes -= sizeOf(p)
+		stats.Expanded++
+		if p.Row() == n {
+			stats.Solutions++
+			if prof != nil {
+				prof.Record(n, 0, 0)
+			}
+			if yield != nil && !yield(p.Locs) {
+				break
+			}
+			continue
+		}
+		kids := children(p)
+		if prof != nil {
+			prof.Record(p.Row(), n, len(kids))
+		}
+		for _, child := range kids {
+			queue = append(queue, child)
+			bytes += sizeOf(child)
+			stats.Generated++
+		}
+		stats.track(len(queue)-head, bytes)
+		if head > len(queue)/2 {
+			queue = append(queue[:0], queue[head:]...)
+			head = 0
+		}
+	}
+	stats.Elapsed = time.Since(start)
+	return stats
+}
+
+// Count returns the number of solutions on an n×n board.
+func (b BFS) Count(n int) int {
+	return b.Search(n, nil).Solutions
+}
+
+// Solutions yields every solution in breadth-first order.
+func (b BFS) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		b.Search(n, yield)
+	}
+}
+
+// First returns the first solution breadth-first order reaches.
+func (b BFS) First(n int) ([]int, bool) {
+	return first(b.Solutions(n))
+}
+
+// IDDFS is an iterative-deepening search: it runs a depth-limited DFS with an
+// explicit stack for limits 0, 1, ..., n, regenerating the shallow levels on
+// every iteration in exchange for a frontier no larger than a DFS stack.
+// Solutions only appear on the final iteration, where the limit reaches n.
+// It panics for boards larger than MaxBitmaskN.
+type IDDFS struct{}
+
+// Search runs the iterative-deepening search on an n×n board, calling yield
+// with each solution; returning false from yield stops the search. A nil
+// yield counts every solution. Nodes are counted across all iterations.
+func (d IDDFS) Search(n int, yield func([]int) bool) SearchStats {
+	return d.search(n, yield, nil)
+}
+
+// search runs the iterative-deepening search, recording every node of every
+// iteration in prof when it is not nil.
+func (IDDFS) search(n int, yield func([]int) bool, prof *profile.Profile) SearchStats {
+	start := time.Now()
+	fullMask(n)
+
+	var stats SearchStats
+	for limit := 0; limit <= n; limit++ {
+		if !depthLimited(n, limit, yield, &stats, prof) {
+			break
+		}
+	}
+	stats.Elapsed = time.Since(start)
+	return stats
+}
+
+// depthLimited runs one DFS iteration that does not expand boards with limit queens.
+// Boards cut off at the limit are recorded in prof as nodes without counting as leaves.
+// return: false if yield stopped the search
+func depthLimited(n, limit int, yield func([]int) bool, stats *SearchStats, prof *profile.Profile) bool {
+	stack := []Partial{{N: n}}
+	bytes := sizeOf(stack[0])
+	stats.Generated++
+	stats.track(1, bytes)
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		bytes -= sizeOf(p)
+		stats.Expanded++
+		if p.Row() == n {
+			stats.Solutions++
+			if prof != nil {
+				prof.Record(n, 0, 0)
+			}
+			if yield != nil && !yield(p.Locs) {
+				return false
+			}
+			continue
+		}
+		if p.Row() == limit {
+			if prof != nil {
+				prof.Grow(limit + 1)
+				prof.Nodes[limit]++
+			}
+			continue
+		}
+		// Push in reverse so the leftmost column is explored first
+		kids := children(p)
+		if prof != nil {
+			prof.Record(p.Row(), n, len(kids))
+		}
+		for i := len(kids) - 1; i >= 0; i-- {
+			stack = append(stack, kids[i])
+			bytes += sizeOf(kids[i])
+			stats.Generated++
+		}
+		stats.track(len(stack), bytes)
+	}
+	return true
+}
+
+// Count returns the number of solutions on an n×n board.
+func (d IDDFS) Count(n int) int {
+	return d.Search(n, nil).Solutions
+}
+
+// Solutions yields every solution in depth-first order.
+func (d IDDFS) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		d.Search(n, yield)
+	}
+}
+
+// First returns the lexicographically smallest solution.
+func (d IDDFS) First(n int) ([]int, bool) {
+	return first(d.Solutions(n))
+}
+
+// Profile records the breadth-first search tree, which is the bitmask tree
+// visited level by level.
+func (b BFS) Profile(n int) *profile.Profile {
+	prof := profile.New(n)
+	stats := b.search(n, nil, prof)
+	prof.Solutions = stats.Solutions
+	prof.Finish(stats.Elapsed)
+	return prof
+}
+
+// Profile records every iteration of the search, so the shallow depths are
+// counted once per iteration that reaches them.
+func (d IDDFS) Profile(n int) *profile.Profile {
+	prof := profile.New(n)
+	stats := d.search(n, nil, prof)
+	prof.Solutions = stats.Solutions
+	prof.Finish(stats.Elapsed)
+	return prof
+}
@@ -0,0 +1,39 @@
+package nqueen
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUninformedSearch(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		want := (Bitmask{}).Count(n)
+		bfs := BFS{}.Search(n, nil)
+		iddfs := IDDFS{}.Search(n, nil)
+		if bfs.Solutions != want || iddfs.Solutions != want {
+			t.Errorf("For n=%d, expected %d solutions, but BFS got %d and IDDFS got %d", n, want, bfs.Solutions, iddfs.Solutions)
+		}
+
+		// BFS sees every node once, so it matches the size of the pruned tree
+		if bfs.Generated != bfs.Expanded {
+			t.Errorf("For n=%d, BFS generated %d nodes but expanded %d", n, bfs.Generated, bfs.Expanded)
+		}
+		// IDDFS regenerates the shallow levels but keeps a smaller frontier
+		if iddfs.Generated <= bfs.Generated && n > 1 {
+			t.Errorf("For n=%d, expected IDDFS to generate more than BFS's %d nodes, but got %d", n, bfs.Generated, iddfs.Generated)
+		}
+		if iddfs.MaxFrontier > bfs.MaxFrontier || iddfs.MaxFrontierBytes > bfs.MaxFrontierBytes {
+			t.Errorf("For n=%d, expected the IDDFS frontier (%d nodes, %d bytes) to be no larger than BFS (%d nodes, %d bytes)",
+				n, iddfs.MaxFrontier, iddfs.MaxFrontierBytes, bfs.MaxFrontier, bfs.MaxFrontierBytes)
+		}
+	}
+
+	// Depth-first order finds the same first solution as the bitmask search
+	want, _ := (Bitmask{}).First(8)
+	if got, ok := (IDDFS{}).First(8); !ok || !slices.Equal(got, want) {
+		t.Errorf("expected IDDFS to find %v first, but got %v", want, got)
+	}
+	if got, ok := (BFS{}).First(8); !ok || !IsValid(got) {
+		t.Errorf("BFS returned invalid first solution %v", got)
+	}
+}
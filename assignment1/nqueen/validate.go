@@ -0,0 +1,160 @@
+package nqueen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConflictKind is the line along which two queens attack each other.
+type ConflictKind int
+
+const (
+	RowConflict ConflictKind = iota
+	ColumnConflict
+	DiagonalConflict
+)
+
+var conflictNames = []string{"row", "column", "diagonal"}
+
+func (k ConflictKind) String() string {
+	if k < 0 || int(k) >= len(conflictNames) {
+		return fmt.Sprintf("ConflictKind(%d)", int(k))
+	}
+	return conflictNames[k]
+}
+
+// Conflict is a pair of queens that attack each other.
+type Conflict struct {
+	A, B Square
+	Kind ConflictKind
+}
+
+// conflict extends the pairwise check of IsValid to boards that are not
+// permutations: it reports whether queens on a and b attack each other, and
+// along which line.
+func conflict(a, b Square) (ConflictKind, bool) {
+	switch {
+	case a.Row == b.Row:
+		return RowConflict, true
+	case a.Col == b.Col:
+		return ColumnConflict, true
+	case onDiagonal(a.Row, a.Col, b.Row, b.Col):
+		return DiagonalConflict, true
+	}
+	return 0, false
+}
+
+// ConflictingPairs returns every pair of queens that attack each other, in
+// the order the queens are given. Unlike Conflicts it takes boards that are
+// not permutations and names each pair's shared line.
+func ConflictingPairs(queens []Square) []Conflict {
+	var conflicts []Conflict
+	for i := 0; i < len(queens); i++ {
+		for j := i + 1; j < len(queens); j++ {
+			if kind, ok := conflict(queens[i], queens[j]); ok {
+				conflicts = append(conflicts, Conflict{queens[i], queens[j], kind})
+			}
+		}
+	}
+	return conflicts
+}
+
+// Placement is a board of any number of queens, as read by ParsePlacement.
+type Placement struct {
+	N      int
+	Queens []Square // in row-major order
+	Format string   // permutation, fen or grid
+}
+
+// IsSolution reports whether the placement has n queens and none of them
+// attack each other.
+func (p Placement) IsSolution() bool {
+	return len(p.Queens) == p.N && len(ConflictingPairs(p.Queens)) == 0
+}
+
+// ParsePlacement reads a board written in one of three notations:
+//
+//   - a permutation, the column of the queen in each row from the top, as
+//     in "1 3 0 2" or "[1,3,0,2]";
+//   - FEN-like ranks from the top separated by slashes, with Q for a queen
+//     and a number for a run of empty squares, as in ".Q2/3Q/Q3/2Q1" or
+//     "1Q2/3Q/Q3/2Q1";
+//   - an ASCII grid as read by ReadBoard, one row per line, which may hold
+//     more than one queen per row.
+func ParsePlacement(s string) (Placement, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return Placement{}, fmt.Errorf("empty board")
+	case strings.Contains(s, "\n"):
+		return parseGrid(s)
+	case strings.Contains(s, "/"):
+		return parseFEN(s)
+	case strings.Trim(s, "0123456789,[]() \t") == "":
+		return parsePermutation(s)
+	case strings.Trim(s, "Qq. \t") == "":
+		return parseGrid(s)
+	}
+	return Placement{}, fmt.Errorf("board %q is not a permutation, FEN-like ranks or an ASCII grid", s)
+}
+
+func parseGrid(s string) (Placement, error) {
+	n, queens, err := readGrid(strings.NewReader(s))
+	if err != nil {
+		return Placement{}, err
+	}
+	return Placement{N: n, Queens: queens, Format: "grid"}, nil
+}
+
+func parsePermutation(s string) (Placement, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(",[]() \t", r)
+	})
+	if len(fields) == 0 {
+		return Placement{}, fmt.Errorf("empty permutation")
+	}
+	p := Placement{N: len(fields), Format: "permutation"}
+	for row, f := range fields {
+		col, err := strconv.Atoi(f)
+		if err != nil {
+			return Placement{}, fmt.Errorf("row %d: %v", row, err)
+		}
+		if col >= p.N {
+			return Placement{}, fmt.Errorf("row %d: column %d is outside the %d×%d board", row, col, p.N, p.N)
+		}
+		p.Queens = append(p.Queens, Square{row, col})
+	}
+	return p, nil
+}
+
+func parseFEN(s string) (Placement, error) {
+	ranks := strings.Split(s, "/")
+	p := Placement{N: len(ranks), Format: "fen"}
+	for row, rank := range ranks {
+		col := 0
+		for i := 0; i < len(rank); i++ {
+			switch ch := rank[i]; {
+			case ch == 'Q' || ch == 'q':
+				p.Queens = append(p.Queens, Square{row, col})
+				col++
+			case ch == '.':
+				col++
+			case ch >= '0' && ch <= '9':
+				j := i
+				for j < len(rank) && rank[j] >= '0' && rank[j] <= '9' {
+					j++
+				}
+				empty, _ := strconv.Atoi(rank[i:j])
+				col += empty
+				i = j - 1
+			default:
+				return Placement{}, fmt.Errorf("rank %d: unexpected character %q", row, ch)
+			}
+		}
+		if col != p.N {
+			return Placement{}, fmt.Errorf("rank %d: expected %d squares, got %d", row, p.N, col)
+		}
+	}
+	return p, nil
+}
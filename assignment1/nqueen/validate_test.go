@@ -0,0 +1,81 @@
+package nqueen
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestParsePlacement(t *testing.T) {
+
+	// The same solution in every notation, including PrintLocs output
+	var grid bytes.Buffer
+	PrintLocs(&grid, []int{1, 3, 0, 2})
+	want := []Square{{0, 1}, {1, 3}, {2, 0}, {3, 2}}
+	tests := []struct {
+		board  string
+		format string
+	}{
+		{"1,3,0,2", "permutation"},
+		{"[1 3 0 2]", "permutation"},
+		{"1Q2/3Q/Q3/2Q1", "fen"},
+		{".Q../...Q/Q.../..Q.", "fen"},
+		{grid.String(), "grid"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePlacement(tt.board)
+		if err != nil {
+			t.Errorf("For %q, unexpected error: %v", tt.board, err)
+			continue
+		}
+		if p.N != 4 || p.Format != tt.format || !slices.Equal(p.Queens, want) || !p.IsSolution() {
+			t.Errorf("For %q, expected the %s solution %v, but got %+v", tt.board, tt.format, want, p)
+		}
+	}
+
+	for _, bad := range []string{"", "1,3,0,4", "1Q2/3Q/Q3", "Q9/", "1,x,0", "Q.\nQ", "[]", ","} {
+		if _, err := ParsePlacement(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+
+	// Ten-wide ranks need two-digit runs
+	p, err := ParsePlacement("Q9/10/10/10/10/10/10/10/10/9Q")
+	if err != nil || p.N != 10 || !slices.Equal(p.Queens, []Square{{0, 0}, {9, 9}}) {
+		t.Errorf("expected queens on 0:0 and 9:9 of a 10×10 board, but got %+v (%v)", p, err)
+	}
+}
+
+func TestConflictingPairs(t *testing.T) {
+	p, err := ParsePlacement("Q.Q.\n....\n..Q.\n...Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Conflict{
+		{Square{0, 0}, Square{0, 2}, RowConflict},
+		{Square{0, 0}, Square{2, 2}, DiagonalConflict},
+		{Square{0, 0}, Square{3, 3}, DiagonalConflict},
+		{Square{0, 2}, Square{2, 2}, ColumnConflict},
+		{Square{2, 2}, Square{3, 3}, DiagonalConflict},
+	}
+	if got := ConflictingPairs(p.Queens); !slices.Equal(got, want) {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+	if p.IsSolution() {
+		t.Errorf("expected %v not to be a solution", p.Queens)
+	}
+
+	// On permutations it agrees with IsValid
+	for locs := range (Permutation{}).Solutions(6) {
+		queens := make([]Square, len(locs))
+		for r, c := range locs {
+			queens[r] = Square{r, c}
+		}
+		if len(ConflictingPairs(queens)) != 0 {
+			t.Errorf("expected no conflicts in solution %v", locs)
+		}
+	}
+	if got := ConflictingPairs([]Square{{0, 0}, {1, 1}, {2, 2}}); len(got) != 3 || got[0].Kind.String() != "diagonal" {
+		t.Errorf("expected three diagonal conflicts, but got %v", got)
+	}
+}
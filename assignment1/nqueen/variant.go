@@ -0,0 +1,222 @@
+package nqueen
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/profile"
+)
+
+// Square is a square of the board.
+type Square struct {
+	Row, Col int
+}
+
+// ParseSquares reads squares written as "row:col" pairs separated by commas, e.g. "0:3,4:1".
+func ParseSquares(s string) ([]Square, error) {
+	var squares []Square
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		rs, cs, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("square %q: expected row:col", pair)
+		}
+		r, err := strconv.Atoi(strings.TrimSpace(rs))
+		if err != nil {
+			return nil, fmt.Errorf("square %q: %v", pair, err)
+		}
+		c, err := strconv.Atoi(strings.TrimSpace(cs))
+		if err != nil {
+			return nil, fmt.Errorf("square %q: %v", pair, err)
+		}
+		squares = append(squares, Square{r, c})
+	}
+	return squares, nil
+}
+
+// Model decides where pieces may stand and which pairs of pieces threaten
+// each other. Every model places one piece per row, so rows never clash.
+type Model interface {
+	// Attacks reports whether pieces on (r1, c1) and (r2, c2) of an n×n
+	// board, with r1 < r2, threaten each other.
+	Attacks(n, r1, c1, r2, c2 int) bool
+	// Open reports whether a piece may be placed on (r, c).
+	Open(r, c int) bool
+}
+
+// Standard is the usual queen: it attacks along its column and both diagonals.
+type Standard struct{}
+
+// Attacks reports whether the queens share a column or a diagonal.
+func (Standard) Attacks(n, r1, c1, r2, c2 int) bool {
+	return c1 == c2 || abs(c1-c2) == r2-r1
+}
+
+// Open reports that every square may hold a queen.
+func (Standard) Open(r, c int) bool { return true }
+
+// Toroidal is a queen on a board whose edges wrap around, so every diagonal
+// continues on the opposite side (OEIS A051906). Solutions exist only when
+// n is coprime to 6.
+type Toroidal struct{}
+
+// Attacks reports whether the queens share a column or a diagonal wrapped
+// around the board edges.
+func (Toroidal) Attacks(n, r1, c1, r2, c2 int) bool {
+	dr := r2 - r1
+	return c1 == c2 || ((c2-c1-dr)%n+n)%n == 0 || ((c2-c1+dr)%n+n)%n == 0
+}
+
+// Open reports that every square may hold a queen.
+func (Toroidal) Open(r, c int) bool { return true }
+
+// SuperQueen is a queen that also moves like a knight (OEIS A051223);
+// the smallest board with a solution is 10×10.
+type SuperQueen struct{}
+
+// Attacks reports whether the pieces attack each other as queens or are a
+// knight's move apart.
+func (SuperQueen) Attacks(n, r1, c1, r2, c2 int) bool {
+	dr, dc := r2-r1, abs(c2-c1)
+	return Standard{}.Attacks(n, r1, c1, r2, c2) || (dr == 1 && dc == 2) || (dr == 2 && dc == 1)
+}
+
+// Open reports that every square may hold a piece.
+func (SuperQueen) Open(r, c int) bool { return true }
+
+// Blocked wraps a model with squares that may not hold a piece. Blocked
+// squares do not stop attacks passing through them.
+type Blocked struct {
+	Model
+	squares []Square // in the order given
+	blocked map[Square]bool
+}
+
+// NewBlocked returns base with the given squares blocked.
+func NewBlocked(base Model, squares []Square) Blocked {
+	b := Blocked{Model: base, squares: squares, blocked: map[Square]bool{}}
+	for _, s := range squares {
+		b.blocked[s] = true
+	}
+	return b
+}
+
+// Open reports whether (r, c) is not blocked and is open in the wrapped model.
+func (b Blocked) Open(r, c int) bool {
+	return !b.blocked[Square{r, c}] && b.Model.Open(r, c)
+}
+
+// Validate checks that every blocked square lies on an n×n board.
+func (b Blocked) Validate(n int) error {
+	for _, s := range b.squares {
+		if s.Row < 0 || s.Row >= n || s.Col < 0 || s.Col >= n {
+			return fmt.Errorf("blocked square %d:%d is outside the %d×%d board", s.Row, s.Col, n, n)
+		}
+	}
+	return nil
+}
+
+// Variant solves the n-Queens problem under any Model. Before searching it
+// tabulates, for every square, the squares of each later row its piece
+// attacks as bitmasks; the search then places one piece per row and prunes
+// with those masks as the bitmask solver does.
+// It panics for boards larger than MaxBitmaskN.
+type Variant struct {
+	Model Model
+}
+
+// attackTable returns att[r][c][r2], the columns of row r2 > r attacked from
+// (r, c), and the open columns of every row.
+func (v Variant) attackTable(n int) ([][][]uint64, []uint64) {
+	fullMask(n)
+	att := make([][][]uint64, n)
+	open := make([]uint64, n)
+	for r := 0; r < n; r++ {
+		att[r] = make([][]uint64, n)
+		for c := 0; c < n; c++ {
+			if v.Model.Open(r, c) {
+				open[r] |= 1 << c
+			}
+			att[r][c] = make([]uint64, n)
+			for r2 := r + 1; r2 < n; r2++ {
+				for c2 := 0; c2 < n; c2++ {
+					if v.Model.Attacks(n, r, c, r2, c2) {
+						att[r][c][r2] |= 1 << c2
+					}
+				}
+			}
+		}
+	}
+	return att, open
+}
+
+// DFS over rows where avail[depth] holds the columns still safe in each row
+// yield - called with each complete board; returning false stops the search
+// prof - records every node when not nil; closed squares are not counted as pruned
+// return: false if the search was stopped
+func walkVariant(att [][][]uint64, avail [][]uint64, row int, locs []int, yield func([]int) bool, prof *profile.Profile) bool {
+	n := len(locs)
+	if row == n {
+		if prof != nil {
+			prof.Record(row, 0, 0)
+		}
+		return yield(locs)
+	}
+	cand := avail[row][row]
+	if prof != nil {
+		prof.Record(row, bits.OnesCount64(avail[0][row]), bits.OnesCount64(cand))
+	}
+	for cand != 0 {
+		bit := cand & -cand
+		cand ^= bit
+		c := bits.TrailingZeros64(bit)
+		locs[row] = c
+		// Remove the squares the new piece attacks from the rows below
+		next := avail[row+1]
+		for r2 := row + 1; r2 < n; r2++ {
+			next[r2] = avail[row][r2] &^ att[row][c][r2]
+		}
+		if !walkVariant(att, avail, row+1, locs, yield, prof) {
+			return false
+		}
+	}
+	return true
+}
+
+// walk runs the search on an n×n board, recording every node in prof when it is not nil.
+func (v Variant) walk(n int, yield func([]int) bool, prof *profile.Profile) {
+	att, open := v.attackTable(n)
+	avail := make([][]uint64, n+1)
+	for i := range avail {
+		avail[i] = make([]uint64, n)
+	}
+	copy(avail[0], open)
+	walkVariant(att, avail, 0, make([]int, n), yield, prof)
+}
+
+// Solutions yields every solution in lexicographic order.
+func (v Variant) Solutions(n int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		v.walk(n, yield, nil)
+	}
+}
+
+// Count returns the number of solutions on an n×n board.
+func (v Variant) Count(n int) int {
+	count := 0
+	for range v.Solutions(n) {
+		count++
+	}
+	return count
+}
+
+// First returns the lexicographically smallest solution.
+func (v Variant) First(n int) ([]int, bool) {
+	return first(v.Solutions(n))
+}
@@ -0,0 +1,71 @@
+package nqueen
+
+import "testing"
+
+func TestVariants(t *testing.T) {
+
+	// Known counts: A000170 (standard), A051906 (toroidal), A051223 (super-queens)
+	tests := []struct {
+		name   string
+		model  Model
+		counts map[int]int
+	}{
+		{"standard", Standard{}, map[int]int{1: 1, 4: 2, 6: 4, 8: 92, 9: 352}},
+		{"toroidal", Toroidal{}, map[int]int{1: 1, 4: 0, 5: 10, 6: 0, 7: 28, 8: 0, 11: 88}},
+		{"superqueen", SuperQueen{}, map[int]int{1: 1, 8: 0, 9: 0, 10: 4, 11: 44}},
+	}
+
+	for _, tt := range tests {
+		v := Variant{Model: tt.model}
+		for n, want := range tt.counts {
+			if got := v.Count(n); got != want {
+				t.Errorf("%s: for n=%d, expected %d solutions, but got %d", tt.name, n, want, got)
+			}
+		}
+	}
+
+	// Super-queen solutions are also standard solutions
+	for locs := range (Variant{Model: SuperQueen{}}).Solutions(10) {
+		if !IsValid(locs) {
+			t.Errorf("super-queen solution %v is not a queen solution", locs)
+		}
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	blocked := []Square{{0, 0}, {3, 4}, {5, 2}}
+	v := Variant{Model: NewBlocked(Standard{}, blocked)}
+
+	// Blocking squares must remove exactly the solutions that use them
+	want := 0
+	for locs := range (Bitmask{}).Solutions(8) {
+		ok := true
+		for _, s := range blocked {
+			if locs[s.Row] == s.Col {
+				ok = false
+			}
+		}
+		if ok {
+			want++
+		}
+	}
+	if got := v.Count(8); got != want {
+		t.Errorf("expected %d solutions avoiding the blocked squares, but got %d", want, got)
+	}
+
+	// Blocking every square of a row leaves no solution
+	row := []Square{{2, 0}, {2, 1}, {2, 2}, {2, 3}}
+	if got := (Variant{Model: NewBlocked(Standard{}, row)}).Count(4); got != 0 {
+		t.Errorf("expected no solutions with row 2 blocked, but got %d", got)
+	}
+
+	// Squares off the board are reported rather than ignored
+	if err := NewBlocked(Standard{}, blocked).Validate(8); err != nil {
+		t.Errorf("expected the blocked squares to fit an 8×8 board, but got %v", err)
+	}
+	for _, s := range []Square{{8, 0}, {0, 8}, {-1, 3}} {
+		if err := NewBlocked(Standard{}, append(blocked, s)).Validate(8); err == nil {
+			t.Errorf("expected an error for blocked square %v on an 8×8 board, but got none", s)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package placement counts and finds placements of non-attacking chess pieces
+// of a single type on a rectangular board. It generalizes the n-Queens search
+// of package nqueen to rooks, bishops, knights, kings and queens on n×m boards.
+package placement
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// Piece is the type of chess piece being placed.
+type Piece int
+
+const (
+	Rook Piece = iota
+	Bishop
+	Knight
+	King
+	Queen
+)
+
+var pieceNames = []string{"rook", "bishop", "knight", "king", "queen"}
+
+func (p Piece) String() string {
+	if p < 0 || int(p) >= len(pieceNames) {
+		return fmt.Sprintf("Piece(%d)", int(p))
+	}
+	return pieceNames[p]
+}
+
+// ParsePiece returns the piece with the given lower-case name.
+func ParsePiece(name string) (Piece, error) {
+	for i, s := range pieceNames {
+		if s == name {
+			return Piece(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown piece %q: use rook, bishop, knight, king or queen", name)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Attacks reports whether pieces of this type on two distinct squares threaten each other.
+// Pieces never block each other, since a piece in between would itself be attacked.
+func (p Piece) Attacks(a, b nqueen.Square) bool {
+	dr, dc := abs(a.Row-b.Row), abs(a.Col-b.Col)
+	switch p {
+	case Rook:
+		return dr == 0 || dc == 0
+	case Bishop:
+		return dr == dc
+	case Knight:
+		return (dr == 1 && dc == 2) || (dr == 2 && dc == 1)
+	case King:
+		return max(dr, dc) == 1
+	case Queen:
+		return dr == 0 || dc == 0 || dr == dc
+	}
+	return false
+}
+
+// bitset is a set of board squares indexed row by row.
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (s bitset) set(i int)      { s[i/64] |= 1 << (i % 64) }
+func (s bitset) has(i int) bool { return s[i/64]&(1<<(i%64)) != 0 }
+
+// countFrom returns the number of members at index i or later.
+func (s bitset) countFrom(i int) int {
+	w := i / 64
+	count := bits.OnesCount64(s[w] >> (i % 64))
+	for _, word := range s[w+1:] {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// nextFrom returns the first member at index i or later, or -1.
+func (s bitset) nextFrom(i int) int {
+	for w := i / 64; w < len(s); w++ {
+		word := s[w]
+		if w == i/64 {
+			word &= ^uint64(0) << (i % 64)
+		}
+		if word != 0 {
+			return w*64 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// Board is a Rows×Cols board on which pieces of a single type are placed.
+type Board struct {
+	Rows, Cols int
+	Piece      Piece
+}
+
+// attackTable returns, for every square, the set of squares its piece attacks.
+func (b Board) attackTable() []bitset {
+	size := b.Rows * b.Cols
+	att := make([]bitset, size)
+	for i := range att {
+		att[i] = newBitset(size)
+		a := nqueen.Square{Row: i / b.Cols, Col: i % b.Cols}
+		for j := 0; j < size; j++ {
+			if j != i && b.Piece.Attacks(a, nqueen.Square{Row: j / b.Cols, Col: j % b.Cols}) {
+				att[i].set(j)
+			}
+		}
+	}
+	return att
+}
+
+// search walks the squares in row-major order, either placing a piece on the
+// next free square or leaving it empty. avail[depth] holds the squares that
+// are neither attacked nor already passed over at that depth.
+// yield - called with the squares of each placement of k pieces; returning false stops the search
+// return: false if the search was stopped
+func (b Board) search(att []bitset, avail []bitset, placed []int, from, k int, yield func([]int) bool) bool {
+	depth := len(placed)
+	if depth == k {
+		return yield(placed)
+	}
+	cur := avail[depth]
+	for i := cur.nextFrom(from); i >= 0; i = cur.nextFrom(i + 1) {
+		// Not enough free squares left for the remaining pieces
+		if cur.countFrom(i) < k-depth {
+			return true
+		}
+		next := avail[depth+1]
+		for w := range next {
+			next[w] = cur[w] &^ att[i][w]
+		}
+		if !b.search(att, avail, append(placed, i), i+1, k, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// walk runs the search for k pieces, yielding the indices of occupied squares.
+func (b Board) walk(k int, yield func([]int) bool) {
+	size := b.Rows * b.Cols
+	if k < 0 || k > size {
+		return
+	}
+	att := b.attackTable()
+	avail := make([]bitset, k+1)
+	for i := range avail {
+		avail[i] = newBitset(size)
+	}
+	for i := 0; i < size; i++ {
+		avail[0].set(i)
+	}
+	b.search(att, avail, make([]int, 0, k), 0, k, yield)
+}
+
+// squares converts square indices to squares.
+func (b Board) squares(idx []int) []nqueen.Square {
+	out := make([]nqueen.Square, len(idx))
+	for i, v := range idx {
+		out[i] = nqueen.Square{Row: v / b.Cols, Col: v % b.Cols}
+	}
+	return out
+}
+
+// Count returns the number of ways to place k non-attacking pieces.
+func (b Board) Count(k int) int {
+	count := 0
+	b.walk(k, func([]int) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Placements yields every placement of k non-attacking pieces, each as its
+// squares in row-major order.
+func (b Board) Placements(k int) iter.Seq[[]nqueen.Square] {
+	return func(yield func([]nqueen.Square) bool) {
+		b.walk(k, func(idx []int) bool {
+			return yield(b.squares(idx))
+		})
+	}
+}
+
+// First returns the first placement of k non-attacking pieces in row-major order.
+func (b Board) First(k int) ([]nqueen.Square, bool) {
+	for p := range b.Placements(k) {
+		return p, true
+	}
+	return nil, false
+}
+
+// Max returns the largest number of non-attacking pieces the board holds
+// and one placement that achieves it. It starts from a greedy placement and
+// raises k until no placement of k pieces exists.
+func (b Board) Max() (int, []nqueen.Square) {
+	best := b.greedy()
+	for {
+		next, ok := b.First(len(best) + 1)
+		if !ok {
+			return len(best), best
+		}
+		best = next
+	}
+}
+
+// greedy places a piece on every square, in row-major order, that is not yet attacked.
+func (b Board) greedy() []nqueen.Square {
+	var placed []nqueen.Square
+	for r := 0; r < b.Rows; r++ {
+		for c := 0; c < b.Cols; c++ {
+			s := nqueen.Square{Row: r, Col: c}
+			free := true
+			for _, p := range placed {
+				if b.Piece.Attacks(p, s) {
+					free = false
+					break
+				}
+			}
+			if free {
+				placed = append(placed, s)
+			}
+		}
+	}
+	return placed
+}
+
+// Valid reports whether the squares are distinct, on the board and pairwise non-attacking.
+func (b Board) Valid(squares []nqueen.Square) bool {
+	for i, s := range squares {
+		if s.Row < 0 || s.Row >= b.Rows || s.Col < 0 || s.Col >= b.Cols {
+			return false
+		}
+		for _, t := range squares[i+1:] {
+			if s == t || b.Piece.Attacks(s, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package placement
+
+import (
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+func TestCount(t *testing.T) {
+
+	// Brute force over every subset of a small board
+	for _, p := range []Piece{Rook, Bishop, Knight, King, Queen} {
+		b := Board{Rows: 3, Cols: 4, Piece: p}
+		size := b.Rows * b.Cols
+		want := make([]int, size+1)
+		for mask := 0; mask < 1<<size; mask++ {
+			var squares []nqueen.Square
+			for i := 0; i < size; i++ {
+				if mask&(1<<i) != 0 {
+					squares = append(squares, nqueen.Square{Row: i / b.Cols, Col: i % b.Cols})
+				}
+			}
+			if b.Valid(squares) {
+				want[len(squares)]++
+			}
+		}
+		for k, w := range want {
+			if got := b.Count(k); got != w {
+				t.Errorf("%v on 3×4: for k=%d, expected %d placements, but got %d", p, k, w, got)
+			}
+		}
+	}
+
+	// Known counts on the 8×8 board
+	tests := []struct {
+		piece  Piece
+		k      int
+		expect int
+	}{
+		{Queen, 8, 92},
+		{Rook, 8, 40320},
+		{Knight, 32, 2},
+		{Bishop, 14, 256},
+	}
+	for _, tt := range tests {
+		if got := (Board{Rows: 8, Cols: 8, Piece: tt.piece}).Count(tt.k); got != tt.expect {
+			t.Errorf("%v on 8×8: for k=%d, expected %d placements, but got %d", tt.piece, tt.k, tt.expect, got)
+		}
+	}
+}
+
+func TestMax(t *testing.T) {
+	tests := []struct {
+		piece      Piece
+		rows, cols int
+		expect     int
+	}{
+		{Rook, 5, 7, 5},
+		{Bishop, 6, 6, 10},
+		{Knight, 4, 4, 8},
+		{Knight, 2, 5, 6},
+		{King, 5, 5, 9},
+		{Queen, 3, 3, 2},
+		{Queen, 6, 6, 6},
+	}
+	for _, tt := range tests {
+		b := Board{Rows: tt.rows, Cols: tt.cols, Piece: tt.piece}
+		k, squares := b.Max()
+		if k != tt.expect || len(squares) != k || !b.Valid(squares) {
+			t.Errorf("%v on %d×%d: expected a valid placement of %d, but got %d: %v",
+				tt.piece, tt.rows, tt.cols, tt.expect, k, squares)
+		}
+	}
+}
+
+func TestParsePiece(t *testing.T) {
+	for _, p := range []Piece{Rook, Bishop, Knight, King, Queen} {
+		if got, err := ParsePiece(p.String()); err != nil || got != p {
+			t.Errorf("ParsePiece(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParsePiece("pawn"); err == nil {
+		t.Errorf("expected an error for an unknown piece")
+	}
+}
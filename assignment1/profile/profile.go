@@ -0,0 +1,66 @@
+// Package profile records the shape of a search tree, node by node, for the
+// tree searches of the nqueen, csp and dlx packages.
+package profile
+
+import "time"
+
+// Profile describes the shape of a search tree: how many nodes were visited
+// and how many candidates were pruned at each depth (the number of queens
+// placed, or of variables assigned), how many nodes had no children, and how
+// fast the search ran. It marshals to JSON for plotting.
+type Profile struct {
+	Solver    string  `json:"solver,omitempty"`
+	N         int     `json:"n"`
+	Solutions int     `json:"solutions"`
+	Nodes     []int64 `json:"nodes_per_depth"`
+	Prunes    []int64 `json:"prunes_per_depth"`
+	// Leaves counts nodes without children: solutions and dead ends
+	Leaves             int64   `json:"leaves"`
+	WallSeconds        float64 `json:"wall_seconds"`
+	SolutionsPerSecond float64 `json:"solutions_per_second"`
+}
+
+// New returns an empty profile of an n×n board with counts for depths 0..n.
+func New(n int) *Profile {
+	p := &Profile{N: n}
+	p.Grow(n + 1)
+	return p
+}
+
+// Record adds a node at the given depth that considered candidates squares
+// and kept children of them; the rest count as pruned.
+func (p *Profile) Record(depth, candidates, children int) {
+	p.Grow(depth + 1)
+	p.Nodes[depth]++
+	p.Prunes[depth] += int64(candidates - children)
+	if children == 0 {
+		p.Leaves++
+	}
+}
+
+// Grow extends the per-depth counts to cover the given number of depths.
+func (p *Profile) Grow(depths int) {
+	for len(p.Nodes) < depths {
+		p.Nodes = append(p.Nodes, 0)
+		p.Prunes = append(p.Prunes, 0)
+	}
+}
+
+// Merge adds the counts of another profile of the same board.
+func (p *Profile) Merge(q *Profile) {
+	p.Grow(len(q.Nodes))
+	for d := range q.Nodes {
+		p.Nodes[d] += q.Nodes[d]
+		p.Prunes[d] += q.Prunes[d]
+	}
+	p.Solutions += q.Solutions
+	p.Leaves += q.Leaves
+}
+
+// Finish sets the wall time of the search and the solution rate.
+func (p *Profile) Finish(elapsed time.Duration) {
+	p.WallSeconds = elapsed.Seconds()
+	if p.WallSeconds > 0 {
+		p.SolutionsPerSecond = float64(p.Solutions) / p.WallSeconds
+	}
+}
@@ -0,0 +1,30 @@
+package profile
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestProfile(t *testing.T) {
+	p := New(2)
+	p.Record(0, 2, 2)
+	p.Record(1, 2, 0)
+	p.Record(3, 0, 0) // past the board grows the counts
+	q := New(2)
+	q.Record(1, 2, 1)
+	q.Solutions = 1
+	p.Merge(q)
+
+	if !slices.Equal(p.Nodes, []int64{1, 2, 0, 1}) || !slices.Equal(p.Prunes, []int64{0, 3, 0, 0}) {
+		t.Errorf("expected nodes [1 2 0 1] and prunes [0 3 0 0], but got %v and %v", p.Nodes, p.Prunes)
+	}
+	if p.Leaves != 2 || p.Solutions != 1 {
+		t.Errorf("expected 2 leaves and 1 solution, but got %d and %d", p.Leaves, p.Solutions)
+	}
+
+	p.Finish(500 * time.Millisecond)
+	if p.WallSeconds != 0.5 || p.SolutionsPerSecond != 2 {
+		t.Errorf("expected 0.5s at 2 solutions per second, but got %vs at %v", p.WallSeconds, p.SolutionsPerSecond)
+	}
+}
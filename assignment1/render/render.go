@@ -0,0 +1,152 @@
+// Package render draws N-Queens boards, and the steps of a traced search, as
+// SVG or PNG pictures with gonum/plot.
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// Side is the width and height of a saved picture.
+const Side = 5 * vg.Inch
+
+var (
+	lightSquare = color.RGBA{R: 240, G: 217, B: 181, A: 255}
+	darkSquare  = color.RGBA{R: 181, G: 136, B: 99, A: 255}
+	queenColor  = color.RGBA{R: 20, G: 20, B: 40, A: 255}
+	attackColor = color.NRGBA{R: 200, G: 30, B: 30, A: 110}
+
+	// eventColors highlights the square of each kind of trace step
+	eventColors = map[nqueen.Event]color.Color{
+		nqueen.Place:     color.NRGBA{R: 60, G: 170, B: 60, A: 160},
+		nqueen.Backtrack: color.NRGBA{R: 120, G: 120, B: 120, A: 160},
+		nqueen.Prune:     color.NRGBA{R: 220, G: 40, B: 40, A: 180},
+		nqueen.Found:     color.NRGBA{R: 250, G: 200, B: 20, A: 200},
+	}
+)
+
+// Board is a picture of an n×n board with a queen in column Locs[r] of each
+// row r from the top; rows past len(Locs) are empty, as on a partly filled
+// board.
+type Board struct {
+	N    int
+	Locs []int
+	// Attacks overlays the row, column and diagonals each queen attacks
+	Attacks bool
+	// Mark, if Highlight is not nil, is a square filled with Highlight
+	Mark      nqueen.Square
+	Highlight color.Color
+	Title     string
+}
+
+// Plot implements plot.Plotter, drawing the board over the data range
+// 0..N on both axes with row 0 at the top.
+func (b Board) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	n := float64(b.N)
+	// corner returns the canvas point of the top-left corner of (row, col)
+	corner := func(row, col float64) vg.Point {
+		return vg.Point{X: trX(col), Y: trY(n - row)}
+	}
+	fill := func(row, col int, clr color.Color) {
+		r, cl := float64(row), float64(col)
+		c.FillPolygon(clr, []vg.Point{corner(r, cl), corner(r, cl+1), corner(r+1, cl+1), corner(r+1, cl)})
+	}
+
+	for row := 0; row < b.N; row++ {
+		for col := 0; col < b.N; col++ {
+			if (row+col)%2 == 0 {
+				fill(row, col, lightSquare)
+			} else {
+				fill(row, col, darkSquare)
+			}
+		}
+	}
+	if b.Highlight != nil {
+		fill(b.Mark.Row, b.Mark.Col, b.Highlight)
+	}
+
+	square := trX(1) - trX(0)
+	if b.Attacks {
+		style := draw.LineStyle{Color: attackColor, Width: square / 12}
+		for row, col := range b.Locs {
+			r, cl := float64(row)+0.5, float64(col)+0.5
+			// Each line runs through the queen's centre to the board edges
+			c.StrokeLine2(style, trX(0), trY(n-r), trX(n), trY(n-r))
+			c.StrokeLine2(style, trX(cl), trY(0), trX(cl), trY(n))
+			before, after := min(r, cl), min(n-r, n-cl)
+			c.StrokeLines(style, []vg.Point{corner(r-before, cl-before), corner(r+after, cl+after)})
+			before, after = min(r, n-cl), min(n-r, cl)
+			c.StrokeLines(style, []vg.Point{corner(r-before, cl+before), corner(r+after, cl-after)})
+		}
+	}
+
+	glyph := draw.GlyphStyle{Color: queenColor, Radius: square * 0.3, Shape: draw.CircleGlyph{}}
+	for row, col := range b.Locs {
+		c.DrawGlyph(glyph, corner(float64(row)+0.5, float64(col)+0.5))
+	}
+}
+
+// plot returns a plot holding only the board.
+func (b Board) plot() *plot.Plot {
+	p := plot.New()
+	p.Title.Text = b.Title
+	p.X.Min, p.X.Max = 0, float64(b.N)
+	p.Y.Min, p.Y.Max = 0, float64(b.N)
+	p.HideAxes()
+	p.Add(b)
+	return p
+}
+
+// Save writes the board to path in the format named by its extension,
+// such as .svg or .png.
+func (b Board) Save(path string) error {
+	return b.plot().Save(Side, Side, path)
+}
+
+// FramePath returns the path of frame i for the frames named by path:
+// trace.png gives trace-00001.png, trace-00002.png and so on.
+func FramePath(path string, i int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%05d%s", strings.TrimSuffix(path, ext), i, ext)
+}
+
+// Frames saves a picture of each step of a traced search on an n×n board,
+// at the paths given by FramePath in a directory created if needed, until
+// steps ends or max frames are written; zero or less means no limit. Each
+// frame highlights the square of its step and is titled with the step. It
+// returns the number of frames written.
+func Frames(path string, n int, steps iter.Seq[nqueen.Step], max int, attacks bool) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+	count := 0
+	for s := range steps {
+		if max > 0 && count == max {
+			break
+		}
+		count++
+		b := Board{
+			N:         n,
+			Locs:      s.Board,
+			Attacks:   attacks,
+			Mark:      nqueen.Square{Row: s.Row, Col: s.Col},
+			Highlight: eventColors[s.Event],
+			Title:     fmt.Sprintf("step %d: %v (%d, %d)", count, s.Event, s.Row, s.Col),
+		}
+		if err := b.Save(FramePath(path, count)); err != nil {
+			return count - 1, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	b := Board{N: 8, Locs: []int{0, 4, 7, 5, 2, 6, 1, 3}, Attacks: true, Title: "8 queens"}
+
+	svg := filepath.Join(dir, "board.svg")
+	if err := b.Save(svg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(svg)
+	if err != nil || !strings.Contains(string(data), "<svg") {
+		t.Errorf("expected an SVG picture, but got %d bytes (%v)", len(data), err)
+	}
+
+	name := filepath.Join(dir, "board.png")
+	if err := b.Save(name); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("expected a PNG picture, but got %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != size.Y || size.X == 0 {
+		t.Errorf("expected a square picture, but got %v", size)
+	}
+
+	if err := b.Save(filepath.Join(dir, "board.bmp")); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.svg")
+	count, err := Frames(path, 4, nqueen.Permutation{}.Trace(4), 10, false)
+	if err != nil || count != 10 {
+		t.Fatalf("expected 10 frames, but got %d (%v)", count, err)
+	}
+	for i := 1; i <= 11; i++ {
+		_, err := os.Stat(FramePath(path, i))
+		if exists := err == nil; exists != (i <= 10) {
+			t.Errorf("expected frame %d to exist: %v, but got %v", i, i <= 10, exists)
+		}
+	}
+	if got := FramePath("out/trace.png", 12); got != "out/trace-00012.png" {
+		t.Errorf("expected out/trace-00012.png, but got %s", got)
+	}
+}
@@ -0,0 +1,152 @@
+// Package sat encodes N-Queens as a propositional formula in conjunctive
+// normal form, reads and writes it in the DIMACS format used by external SAT
+// solvers, and solves it with a small conflict-driven clause learning (CDCL)
+// solver: DPLL with unit propagation, clause learning and backjumping.
+package sat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CNF is a formula in conjunctive normal form. Variables are numbered from 1
+// and a literal is a variable or its negation, as in DIMACS: 3 is x3 and -3
+// is not x3. Each clause is a disjunction of literals.
+type CNF struct {
+	NumVars int
+	Clauses [][]int
+}
+
+// Add appends a clause.
+func (f *CNF) Add(lits ...int) {
+	f.Clauses = append(f.Clauses, lits)
+}
+
+// WriteDIMACS writes f in DIMACS CNF format, preceded by the given comment lines.
+func (f *CNF) WriteDIMACS(w io.Writer, comments ...string) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range comments {
+		fmt.Fprintf(bw, "c %s\n", c)
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", f.NumVars, len(f.Clauses))
+	var line []byte
+	for _, clause := range f.Clauses {
+		line = line[:0]
+		for _, l := range clause {
+			line = strconv.AppendInt(line, int64(l), 10)
+			line = append(line, ' ')
+		}
+		line = append(line, '0', '\n')
+		bw.Write(line)
+	}
+	return bw.Flush()
+}
+
+// ReadDIMACS reads a formula in DIMACS CNF format.
+func ReadDIMACS(r io.Reader) (*CNF, error) {
+	var f *CNF
+	var clause []int
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || fields[0] == "c" || fields[0] == "%" {
+			continue
+		}
+		if fields[0] == "p" {
+			if f != nil {
+				return nil, fmt.Errorf("line %d: second problem line", line)
+			}
+			if len(fields) != 4 || fields[1] != "cnf" {
+				return nil, fmt.Errorf("line %d: expected \"p cnf <variables> <clauses>\"", line)
+			}
+			vars, err := strconv.Atoi(fields[2])
+			if err != nil || vars < 0 {
+				return nil, fmt.Errorf("line %d: invalid variable count %q", line, fields[2])
+			}
+			f = &CNF{NumVars: vars}
+			continue
+		}
+		if f == nil {
+			return nil, fmt.Errorf("line %d: clause before the problem line", line)
+		}
+		for _, s := range fields {
+			l, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid literal %q", line, s)
+			}
+			if l == 0 {
+				f.Clauses = append(f.Clauses, clause)
+				clause = nil
+				continue
+			}
+			if l > f.NumVars || -l > f.NumVars {
+				return nil, fmt.Errorf("line %d: literal %d exceeds %d variables", line, l, f.NumVars)
+			}
+			clause = append(clause, l)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("missing problem line")
+	}
+	if clause != nil {
+		return nil, fmt.Errorf("last clause is not terminated by 0")
+	}
+	return f, nil
+}
+
+// QueenVar returns the variable that is true when a queen stands on row r,
+// column c of an n×n board.
+func QueenVar(n, r, c int) int {
+	return r*n + c + 1
+}
+
+// Queens encodes N-Queens on an n×n board: every row holds at least one
+// queen, and no two queens share a row, column or diagonal. Each fixed queen
+// (row -> column) adds a unit clause, so the formula is satisfiable exactly
+// when the placement can be completed.
+func Queens(n int, fixed map[int]int) *CNF {
+	f := &CNF{NumVars: n * n}
+	for r := 0; r < n; r++ {
+		row := make([]int, n)
+		for c := range row {
+			row[c] = QueenVar(n, r, c)
+		}
+		f.Add(row...)
+	}
+	// At most one queen on each pair of attacking squares
+	for i := 0; i < n*n; i++ {
+		r1, c1 := i/n, i%n
+		for j := i + 1; j < n*n; j++ {
+			r2, c2 := j/n, j%n
+			if r1 == r2 || c1 == c2 || r2-r1 == c2-c1 || r2-r1 == c1-c2 {
+				f.Add(-QueenVar(n, r1, c1), -QueenVar(n, r2, c2))
+			}
+		}
+	}
+	for r := 0; r < n; r++ {
+		if c, ok := fixed[r]; ok {
+			f.Add(QueenVar(n, r, c))
+		}
+	}
+	return f
+}
+
+// DecodeQueens returns the column of the queen in each row of a model of
+// Queens(n, ...), as used by the nqueen package.
+func DecodeQueens(n int, model []bool) []int {
+	locs := make([]int, n)
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			if model[QueenVar(n, r, c)] {
+				locs[r] = c
+			}
+		}
+	}
+	return locs
+}
@@ -0,0 +1,153 @@
+package sat
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/chenIshi/CS220-Data-Analytics/assignment1/nqueen"
+)
+
+// satisfies checks a model against every clause of f.
+func satisfies(f *CNF, model []bool) bool {
+	for _, clause := range f.Clauses {
+		ok := false
+		for _, l := range clause {
+			if l > 0 && model[l] || l < 0 && !model[-l] {
+				ok = true
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSolveRandom(t *testing.T) {
+
+	// Compare with brute force on random 3-SAT near the satisfiability threshold
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		f := &CNF{NumVars: 10}
+		for i := 0; i < 43; i++ {
+			var clause []int
+			for j := 0; j < 3; j++ {
+				l := rng.Intn(f.NumVars) + 1
+				if rng.Intn(2) == 0 {
+					l = -l
+				}
+				clause = append(clause, l)
+			}
+			f.Add(clause...)
+		}
+
+		want := false
+		model := make([]bool, f.NumVars+1)
+		for mask := 0; mask < 1<<f.NumVars && !want; mask++ {
+			for v := 1; v <= f.NumVars; v++ {
+				model[v] = mask&(1<<(v-1)) != 0
+			}
+			want = satisfies(f, model)
+		}
+
+		got, ok, _ := Solve(f)
+		if ok != want {
+			t.Fatalf("trial %d: expected satisfiable=%v, but got %v", trial, want, ok)
+		}
+		if ok && !satisfies(f, got) {
+			t.Fatalf("trial %d: model %v does not satisfy the formula", trial, got)
+		}
+	}
+}
+
+func TestPigeonhole(t *testing.T) {
+
+	// 5 pigeons cannot share 4 holes, which forces conflicts and learning
+	const pigeons, holes = 5, 4
+	f := &CNF{NumVars: pigeons * holes}
+	at := func(p, h int) int { return p*holes + h + 1 }
+	for p := 0; p < pigeons; p++ {
+		var clause []int
+		for h := 0; h < holes; h++ {
+			clause = append(clause, at(p, h))
+		}
+		f.Add(clause...)
+	}
+	for h := 0; h < holes; h++ {
+		for p := 0; p < pigeons; p++ {
+			for q := p + 1; q < pigeons; q++ {
+				f.Add(-at(p, h), -at(q, h))
+			}
+		}
+	}
+	_, ok, stats := Solve(f)
+	if ok {
+		t.Errorf("expected the pigeonhole formula to be unsatisfiable")
+	}
+	if stats.Conflicts == 0 || stats.Learned == 0 || stats.Decisions == 0 {
+		t.Errorf("expected decisions, conflicts and learned clauses, but got %+v", stats)
+	}
+}
+
+func TestQueens(t *testing.T) {
+	for n := 1; n <= 12; n++ {
+		want := (nqueen.Bitmask{}).Count(n) > 0
+		model, ok, _ := Solve(Queens(n, nil))
+		if ok != want {
+			t.Errorf("For n=%d, expected satisfiable=%v, but got %v", n, want, ok)
+			continue
+		}
+		if ok && !nqueen.IsValid(DecodeQueens(n, model)) {
+			t.Errorf("For n=%d, decoded invalid solution %v", n, DecodeQueens(n, model))
+		}
+	}
+
+	// Fixed queens agree with the completion search
+	for _, fixed := range []map[int]int{{0: 0}, {0: 1}, {1: 0, 2: 3}} {
+		want := (nqueen.Completion{Fixed: fixed}).Count(4) > 0
+		model, ok, _ := Solve(Queens(4, fixed))
+		if ok != want {
+			t.Errorf("For fixed %v, expected satisfiable=%v, but got %v", fixed, want, ok)
+		}
+		if ok {
+			locs := DecodeQueens(4, model)
+			for r, c := range fixed {
+				if locs[r] != c {
+					t.Errorf("For fixed %v, solution %v moved the queen in row %d", fixed, locs, r)
+				}
+			}
+		}
+	}
+}
+
+func TestDIMACS(t *testing.T) {
+	f := Queens(4, map[int]int{1: 0})
+	var buf bytes.Buffer
+	if err := f.WriteDIMACS(&buf, "4-Queens"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDIMACS(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, f) {
+		t.Errorf("round trip changed the formula")
+	}
+
+	for _, bad := range []string{"1 2 0\n", "p cnf 2 1\n1 3 0\n", "p cnf 2 1\n1 2\n", "p dnf 2 1\n"} {
+		if _, err := ReadDIMACS(bytes.NewBufferString(bad)); err == nil {
+			t.Errorf("expected an error reading %q", bad)
+		}
+	}
+}
+
+func TestLuby(t *testing.T) {
+	want := []int{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, 1}
+	for i, w := range want {
+		if got := luby(i); got != w {
+			t.Errorf("luby(%d): expected %d, but got %d", i, w, got)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package sat
+
+import "time"
+
+// Stats describes the work the solver did.
+type Stats struct {
+	Decisions    int // branching assignments
+	Conflicts    int // clauses falsified during propagation
+	Propagations int // literals assigned by unit propagation
+	Learned      int // clauses learned from conflicts
+	Restarts     int
+	Elapsed      time.Duration
+}
+
+// Internally literal x_v is 2(v-1) and its negation 2(v-1)+1, so l^1 negates l.
+func encode(l int) int {
+	if l > 0 {
+		return 2 * (l - 1)
+	}
+	return 2*(-l-1) + 1
+}
+
+// solver is the CDCL state. Every clause of two or more literals watches its
+// first two literals; a clause is only visited when a watched literal
+// becomes false.
+type solver struct {
+	clauses  [][]int
+	watches  [][]int // literal -> clauses watching it
+	value    []int8  // per variable: 0 unassigned, 1 true, -1 false
+	level    []int   // decision level of each assigned variable
+	reason   []int   // clause that implied each variable, or -1 for decisions
+	trail    []int   // assigned literals in order
+	trailLim []int   // trail length at the start of each decision level
+	qhead    int     // next trail position to propagate
+	activity []float64
+	inc      float64
+	phase    []int8 // last value of each variable, reused when branching
+	seen     []bool
+	stats    Stats
+}
+
+// litValue returns 1 if literal l is true, -1 if false and 0 if unassigned.
+func (s *solver) litValue(l int) int8 {
+	v := s.value[l>>1]
+	if l&1 == 1 {
+		return -v
+	}
+	return v
+}
+
+func (s *solver) decisionLevel() int { return len(s.trailLim) }
+
+// assign makes literal l true with the given reason clause.
+func (s *solver) assign(l, reason int) {
+	v := l >> 1
+	s.value[v] = 1
+	if l&1 == 1 {
+		s.value[v] = -1
+	}
+	s.level[v] = s.decisionLevel()
+	s.reason[v] = reason
+	s.trail = append(s.trail, l)
+}
+
+// addClause stores a clause of two or more literals and watches its first two.
+func (s *solver) addClause(c []int) int {
+	ci := len(s.clauses)
+	s.clauses = append(s.clauses, c)
+	s.watches[c[0]] = append(s.watches[c[0]], ci)
+	s.watches[c[1]] = append(s.watches[c[1]], ci)
+	return ci
+}
+
+// propagate assigns every literal forced by unit propagation and returns
+// the index of a falsified clause, or -1 if there is none.
+func (s *solver) propagate() int {
+	for s.qhead < len(s.trail) {
+		falsified := s.trail[s.qhead] ^ 1
+		s.qhead++
+		ws := s.watches[falsified]
+		kept := ws[:0]
+		for i := 0; i < len(ws); i++ {
+			ci := ws[i]
+			c := s.clauses[ci]
+			if c[0] == falsified {
+				c[0], c[1] = c[1], c[0]
+			}
+			if s.litValue(c[0]) == 1 {
+				kept = append(kept, ci)
+				continue
+			}
+			// Watch another literal that is not false, if there is one
+			moved := false
+			for k := 2; k < len(c); k++ {
+				if s.litValue(c[k]) != -1 {
+					c[1], c[k] = c[k], c[1]
+					s.watches[c[1]] = append(s.watches[c[1]], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			kept = append(kept, ci)
+			if s.litValue(c[0]) == -1 {
+				kept = append(kept, ws[i+1:]...)
+				s.watches[falsified] = kept
+				return ci
+			}
+			s.assign(c[0], ci)
+			s.stats.Propagations++
+		}
+		s.watches[falsified] = kept
+	}
+	return -1
+}
+
+// analyze derives the first-UIP clause from a conflict and returns it, with
+// the asserting literal first, and the level to backjump to.
+func (s *solver) analyze(confl int) ([]int, int) {
+	learnt := []int{-1}
+	pathC := 0
+	p := -1
+	idx := len(s.trail) - 1
+	for {
+		c := s.clauses[confl]
+		start := 0
+		if p >= 0 {
+			// c[0] of a reason clause is the literal it implied
+			start = 1
+		}
+		for _, q := range c[start:] {
+			v := q >> 1
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.seen[v] = true
+			s.bump(v)
+			if s.level[v] == s.decisionLevel() {
+				pathC++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+		for !s.seen[s.trail[idx]>>1] {
+			idx--
+		}
+		p = s.trail[idx]
+		idx--
+		confl = s.reason[p>>1]
+		s.seen[p>>1] = false
+		pathC--
+		if pathC == 0 {
+			break
+		}
+	}
+	learnt[0] = p ^ 1
+
+	backjump := 0
+	for i := 1; i < len(learnt); i++ {
+		s.seen[learnt[i]>>1] = false
+		if lv := s.level[learnt[i]>>1]; lv > backjump {
+			backjump = lv
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+	return learnt, backjump
+}
+
+// bump raises the activity of a variable seen in a conflict.
+func (s *solver) bump(v int) {
+	s.activity[v] += s.inc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.inc *= 1e-100
+	}
+}
+
+// cancelUntil undoes every assignment above the given decision level.
+func (s *solver) cancelUntil(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := s.trail[i] >> 1
+		s.phase[v] = s.value[v]
+		s.value[v] = 0
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+// pickBranch returns the unassigned variable with the highest activity, or -1.
+func (s *solver) pickBranch() int {
+	best := -1
+	for v, val := range s.value {
+		if val == 0 && (best < 0 || s.activity[v] > s.activity[best]) {
+			best = v
+		}
+	}
+	return best
+}
+
+// Solve decides whether f is satisfiable. If it is, model[v] is the value of
+// variable v in a satisfying assignment (model[0] is unused).
+func Solve(f *CNF) (model []bool, ok bool, stats Stats) {
+	start := time.Now()
+	s := &solver{
+		watches:  make([][]int, 2*f.NumVars),
+		value:    make([]int8, f.NumVars),
+		level:    make([]int, f.NumVars),
+		reason:   make([]int, f.NumVars),
+		activity: make([]float64, f.NumVars),
+		inc:      1,
+		phase:    make([]int8, f.NumVars),
+		seen:     make([]bool, f.NumVars),
+	}
+	defer func() { stats.Elapsed = time.Since(start) }()
+
+	for _, clause := range f.Clauses {
+		c, satisfied := s.simplify(clause)
+		switch {
+		case satisfied:
+		case len(c) == 0:
+			return nil, false, s.stats
+		case len(c) == 1:
+			if s.litValue(c[0]) == -1 {
+				return nil, false, s.stats
+			}
+			if s.litValue(c[0]) == 0 {
+				s.assign(c[0], -1)
+			}
+		default:
+			s.addClause(c)
+		}
+	}
+
+	// Restart on the Luby sequence, keeping learned clauses and activities
+	budget := lubyUnit * luby(0)
+	for {
+		if confl := s.propagate(); confl >= 0 {
+			s.stats.Conflicts++
+			budget--
+			if s.decisionLevel() == 0 {
+				return nil, false, s.stats
+			}
+			learnt, backjump := s.analyze(confl)
+			s.cancelUntil(backjump)
+			if len(learnt) == 1 {
+				s.assign(learnt[0], -1)
+			} else {
+				s.assign(learnt[0], s.addClause(learnt))
+			}
+			s.stats.Learned++
+			s.inc /= 0.95
+			continue
+		}
+
+		if budget <= 0 && s.decisionLevel() > 0 {
+			s.stats.Restarts++
+			s.cancelUntil(0)
+			budget = lubyUnit * luby(s.stats.Restarts)
+			continue
+		}
+		v := s.pickBranch()
+		if v < 0 {
+			break
+		}
+		s.stats.Decisions++
+		s.trailLim = append(s.trailLim, len(s.trail))
+		// Branch on the saved phase, false at first
+		l := 2*v + 1
+		if s.phase[v] == 1 {
+			l = 2 * v
+		}
+		s.assign(l, -1)
+	}
+
+	model = make([]bool, f.NumVars+1)
+	for v, val := range s.value {
+		model[v+1] = val == 1
+	}
+	return model, true, s.stats
+}
+
+// lubyUnit is the number of conflicts in a restart interval of length 1.
+const lubyUnit = 100
+
+// luby returns element i of the Luby sequence 1, 1, 2, 1, 1, 2, 4, 1, ...
+func luby(i int) int {
+	size, seq := 1, 0
+	for size < i+1 {
+		seq++
+		size = 2*size + 1
+	}
+	for size-1 != i {
+		size = (size - 1) / 2
+		seq--
+		i %= size
+	}
+	return 1 << seq
+}
+
+// simplify converts a clause to internal literals, dropping duplicates and
+// literals already false at level 0. It reports clauses that are always true.
+func (s *solver) simplify(clause []int) ([]int, bool) {
+	c := make([]int, 0, len(clause))
+	for _, l := range clause {
+		il := encode(l)
+		switch s.litValue(il) {
+		case 1:
+			return nil, true
+		case -1:
+			continue
+		}
+		dup := false
+		for _, m := range c {
+			if m == il {
+				dup = true
+			} else if m == il^1 {
+				return nil, true
+			}
+		}
+		if !dup {
+			c = append(c, il)
+		}
+	}
+	return c, false
+}